@@ -15,6 +15,32 @@ func NewNode(pk string, blameData, blameSig []byte) Node {
 	}
 }
 
+func newLivenessNode(pk string) Node {
+	node := NewNode(pk, nil, nil)
+	node.Category = CategoryLiveness
+	return node
+}
+
+// CategoryOfReason return the category of the nodes blamed for the given fail reason
+func CategoryOfReason(reason string) string {
+	switch reason {
+	case TssBrokenMsg, HashCheckFail, InvalidControlMsg:
+		return CategoryMalicious
+	default:
+		return CategoryLiveness
+	}
+}
+
+// categorize set the category of the nodes that do not have one yet based on the fail reason
+func categorize(reason string, nodes []Node) []Node {
+	for i := range nodes {
+		if len(nodes[i].Category) == 0 {
+			nodes[i].Category = CategoryOfReason(reason)
+		}
+	}
+	return nodes
+}
+
 func (bn *Node) Equal(node Node) bool {
 	if bn.Pubkey == node.Pubkey && bytes.Equal(bn.BlameSignature, node.BlameSignature) {
 		return true
@@ -26,7 +52,7 @@ func (bn *Node) Equal(node Node) bool {
 func NewBlame(reason string, blameNodes []Node) Blame {
 	return Blame{
 		FailReason: reason,
-		BlameNodes: blameNodes,
+		BlameNodes: categorize(reason, blameNodes),
 	}
 }
 
@@ -47,13 +73,23 @@ func (b Blame) String() string {
 func (b *Blame) SetBlame(reason string, nodes []Node, isUnicast bool) {
 	b.FailReason = reason
 	b.IsUnicast = isUnicast
-	b.BlameNodes = append(b.BlameNodes, nodes...)
+	b.BlameNodes = append(b.BlameNodes, categorize(reason, nodes)...)
 }
 
 func (b *Blame) AlreadyBlame() bool {
 	return len(b.BlameNodes) > 0
 }
 
+// IsMalicious check whether any of the blamed nodes provably misbehaved
+func (b *Blame) IsMalicious() bool {
+	for _, el := range b.BlameNodes {
+		if el.Category == CategoryMalicious {
+			return true
+		}
+	}
+	return false
+}
+
 // AddBlameNodes add nodes to the blame list
 func (b *Blame) AddBlameNodes(newBlameNodes ...Node) {
 	for _, node := range newBlameNodes {
@@ -27,3 +27,18 @@ func (BlameTestSuite) TestBlame(c *C) {
 	b.SetBlame("helloworld", nil, false)
 	c.Assert(b.FailReason, Equals, "helloworld")
 }
+
+func (BlameTestSuite) TestBlameCategory(c *C) {
+	b := NewBlame(TssTimeout, []Node{createNewNode("1")})
+	c.Assert(b.BlameNodes[0].Category, Equals, CategoryLiveness)
+	c.Assert(b.IsMalicious(), Equals, false)
+	// the nodes blamed for timeout keep their category even if the fail reason changes
+	b.AddBlameNodes(newLivenessNode("2"))
+	b.SetBlame(TssBrokenMsg, []Node{createNewNode("3")}, false)
+	c.Assert(b.BlameNodes, HasLen, 3)
+	c.Assert(b.BlameNodes[1].Category, Equals, CategoryLiveness)
+	c.Assert(b.BlameNodes[2].Category, Equals, CategoryMalicious)
+	c.Assert(b.IsMalicious(), Equals, true)
+	c.Assert(CategoryOfReason(HashCheckFail), Equals, CategoryMalicious)
+	c.Assert(CategoryOfReason(TssSyncFail), Equals, CategoryLiveness)
+}
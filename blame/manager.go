@@ -20,6 +20,7 @@ type Manager struct {
 	lastMsgLocker   *sync.RWMutex
 	lastMsg         btss.Message
 	acceptedShares  *sync.Map
+	disabled        bool
 }
 
 func NewBlameManager() *Manager {
@@ -36,7 +37,22 @@ func NewBlameManager() *Manager {
 	}
 }
 
+// SetDisabled turn the blame attribution off, the disabled manager reports an empty blame
+func (m *Manager) SetDisabled(disabled bool) {
+	m.disabled = disabled
+}
+
+// IsDisabled return whether the blame attribution is turned off
+func (m *Manager) IsDisabled() bool {
+	return m.disabled
+}
+
+// GetBlame return the blame of the ceremony, if the blame is disabled, it returns a new empty blame on every call,
+// so whatever the caller sets on it is discarded
 func (m *Manager) GetBlame() *Blame {
+	if m.disabled {
+		return &Blame{}
+	}
 	return m.blame
 }
 
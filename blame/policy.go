@@ -46,6 +46,9 @@ func (m *Manager) tssTimeoutBlame(lastMessageType string, partyIDMap map[string]
 
 // this blame blames the node who cause the timeout in node sync
 func (m *Manager) NodeSyncBlame(keys []string, onlinePeers []peer.ID) (Blame, error) {
+	if m.disabled {
+		return Blame{}, nil
+	}
 	blame := Blame{
 		FailReason: TssSyncFail,
 	}
@@ -62,7 +65,7 @@ func (m *Manager) NodeSyncBlame(keys []string, onlinePeers []peer.ID) (Blame, er
 			}
 		}
 		if !found {
-			blame.BlameNodes = append(blame.BlameNodes, NewNode(item, nil, nil))
+			blame.BlameNodes = append(blame.BlameNodes, newLivenessNode(item))
 		}
 	}
 	return blame, nil
@@ -70,6 +73,9 @@ func (m *Manager) NodeSyncBlame(keys []string, onlinePeers []peer.ID) (Blame, er
 
 // this blame blames the node who cause the timeout in unicast message
 func (m *Manager) GetUnicastBlame(lastMsgType string) ([]Node, error) {
+	if m.disabled {
+		return nil, nil
+	}
 	if len(m.lastUnicastPeer) == 0 {
 		m.logger.Debug().Msg("we do not have any unicast message received yet")
 		return nil, nil
@@ -94,13 +100,16 @@ func (m *Manager) GetUnicastBlame(lastMsgType string) ([]Node, error) {
 	}
 	var blameNodes []Node
 	for _, el := range blamePeers {
-		blameNodes = append(blameNodes, NewNode(el, nil, nil))
+		blameNodes = append(blameNodes, newLivenessNode(el))
 	}
 	return blameNodes, nil
 }
 
 // this blame blames the node who cause the timeout in broadcast message
 func (m *Manager) GetBroadcastBlame(lastMessageType string) ([]Node, error) {
+	if m.disabled {
+		return nil, nil
+	}
 	blamePeers, err := m.tssTimeoutBlame(lastMessageType, m.partyInfo.PartyIDMap)
 	if err != nil {
 		m.logger.Error().Err(err).Msg("fail to get the blamed peers")
@@ -108,7 +117,7 @@ func (m *Manager) GetBroadcastBlame(lastMessageType string) ([]Node, error) {
 	}
 	var blameNodes []Node
 	for _, el := range blamePeers {
-		blameNodes = append(blameNodes, NewNode(el, nil, nil))
+		blameNodes = append(blameNodes, newLivenessNode(el))
 	}
 	return blameNodes, nil
 }
@@ -130,6 +139,9 @@ func (m *Manager) TssWrongShareBlame(wiredMsg *messages.WireMessage) (string, er
 
 // this blame blames the node fail to send the shares to the node
 func (m *Manager) TssMissingShareBlame(rounds int) ([]Node, bool, error) {
+	if m.disabled {
+		return nil, false, nil
+	}
 	cachedShares := make([][]string, rounds)
 	m.acceptedShares.Range(func(key, value interface{}) bool {
 		data := value.([]string)
@@ -174,12 +186,7 @@ func (m *Manager) TssMissingShareBlame(rounds int) ([]Node, bool, error) {
 	}
 	var blameNodes []Node
 	for _, el := range blamePubKeys {
-		node := Node{
-			el,
-			nil,
-			nil,
-		}
-		blameNodes = append(blameNodes, node)
+		blameNodes = append(blameNodes, newLivenessNode(el))
 	}
 	return blameNodes, isUnicast, nil
 }
@@ -88,6 +88,7 @@ func (p *policyTestSuite) TestGetBroadcastBlame(c *C) {
 	var blamePubKeys []string
 	for _, el := range blames {
 		blamePubKeys = append(blamePubKeys, el.Pubkey)
+		c.Assert(el.Category, Equals, CategoryLiveness)
 	}
 	sort.Strings(blamePubKeys)
 	expected := testPubKeys[2:]
@@ -134,3 +135,31 @@ func (p *policyTestSuite) TestTssMissingShareBlame(c *C) {
 	sort.Strings(results)
 	c.Assert(results, DeepEquals, localTestPubKeys[2:])
 }
+
+func (p *policyTestSuite) TestDisableBlame(c *C) {
+	blameMgr := p.blameMgr
+	blameMgr.SetDisabled(true)
+	c.Assert(blameMgr.IsDisabled(), Equals, true)
+	blameMgr.GetBlame().SetBlame(TssTimeout, []Node{newLivenessNode(testPubKeys[0])}, false)
+	c.Assert(blameMgr.GetBlame().BlameNodes, HasLen, 0)
+	c.Assert(blameMgr.GetBlame().FailReason, Equals, "")
+
+	nodes, err := blameMgr.GetUnicastBlame("testType")
+	c.Assert(err, IsNil)
+	c.Assert(nodes, HasLen, 0)
+	nodes, err = blameMgr.GetBroadcastBlame("key1")
+	c.Assert(err, IsNil)
+	c.Assert(nodes, HasLen, 0)
+	blameMgr.GetAcceptShares().Store(RoundInfo{0, "testRound"}, []string{"1"})
+	nodes, _, err = blameMgr.TssMissingShareBlame(1)
+	c.Assert(err, IsNil)
+	c.Assert(nodes, HasLen, 0)
+	syncBlame, err := blameMgr.NodeSyncBlame(testPubKeys[:], nil)
+	c.Assert(err, IsNil)
+	c.Assert(syncBlame.BlameNodes, HasLen, 0)
+
+	blameMgr.SetDisabled(false)
+	syncBlame, err = blameMgr.NodeSyncBlame(testPubKeys[:], nil)
+	c.Assert(err, IsNil)
+	c.Assert(syncBlame.BlameNodes, HasLen, len(testPubKeys))
+}
@@ -39,7 +39,11 @@ func (tr *RoundMgr) Set(key string, msg *messages.WireMessage) {
 	tr.storedMsg[key] = msg
 }
 
+// GetByRound return the nodes we have received the message of the given round from, the round manager is shared
+// by the goroutines of a ceremony, so it holds the lock as Get and Set do
 func (tr *RoundMgr) GetByRound(roundInfo string) []string {
+	tr.storeLocker.Lock()
+	defer tr.storeLocker.Unlock()
 	var standbyNodes []string
 	for _, el := range tr.storedMsg {
 		if el.RoundInfo == roundInfo {
@@ -1,6 +1,11 @@
 package blame
 
 import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	btss "github.com/binance-chain/tss-lib/tss"
 	. "gopkg.in/check.v1"
 
 	"gitlab.com/thorchain/tss/go-tss/messages"
@@ -40,3 +45,32 @@ func (ShareMgrSuite) TestTssRoundMgr(c *C) {
 	ret = mgr.Get("test2")
 	c.Assert(ret.RoundInfo, Equals, "test2")
 }
+
+// run with -race to make sure the round manager is safe to share between the goroutines
+func (RoundMgrSuite) TestTssRoundMgrConcurrent(c *C) {
+	mgr := NewTssRoundMgr()
+	const nodes = 20
+	wg := sync.WaitGroup{}
+	for i := 0; i < nodes; i++ {
+		wg.Add(2)
+		go func(idx int) {
+			defer wg.Done()
+			partyID := btss.NewPartyID(fmt.Sprintf("%d", idx), "", big.NewInt(int64(idx+1)))
+			for _, round := range []string{"round1", "round2"} {
+				mgr.Set(fmt.Sprintf("%s-%d", round, idx), &messages.WireMessage{
+					Routing:   &btss.MessageRouting{From: partyID},
+					RoundInfo: round,
+				})
+			}
+		}(i)
+		go func(idx int) {
+			defer wg.Done()
+			mgr.GetByRound("round1")
+			mgr.Get(fmt.Sprintf("round1-%d", idx))
+		}(i)
+	}
+	wg.Wait()
+	c.Assert(mgr.GetByRound("round1"), HasLen, nodes)
+	c.Assert(mgr.GetByRound("round2"), HasLen, nodes)
+	c.Assert(mgr.GetByRound("round3"), HasLen, 0)
+}
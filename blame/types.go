@@ -12,6 +12,16 @@ const (
 	TssSyncFail   = "signers fail to sync before keygen/keysign"
 	TssBrokenMsg  = "tss share verification failed"
 	InternalError = "fail to start the join party "
+	StorageFail   = "insufficient space to save the key share"
+	// InvalidControlMsg marks the node that signed a share request it is not entitled to
+	InvalidControlMsg = "invalid control message"
+)
+
+const (
+	// CategoryMalicious marks the node that provably misbehaved, e.g. it sent a wrong share
+	CategoryMalicious = "malicious"
+	// CategoryLiveness marks the node that failed to respond in time, which may be caused by network trouble
+	CategoryLiveness = "liveness"
 )
 
 var (
@@ -33,6 +43,8 @@ type Node struct {
 	Pubkey         string `json:"pubkey"`
 	BlameData      []byte `json:"data"`
 	BlameSignature []byte `json:"signature,omitempty"`
+	Category       string `json:"category,omitempty"`
+	TssError       string `json:"tss_error,omitempty"`
 }
 
 // Blame is used to store the blame nodes and the fail reason
@@ -7,16 +7,20 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/binance-chain/go-sdk/common/types"
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/cosmos/cosmos-sdk/client/input"
 	golog "github.com/ipfs/go-log"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
+	tcrypto "github.com/tendermint/tendermint/crypto"
 
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 	"gitlab.com/thorchain/tss/go-tss/tss"
 )
@@ -27,8 +31,18 @@ var (
 	pretty     bool
 	baseFolder string
 	tssAddr    string
+
+	announceAllow  string
+	announceDeny   string
+	preParamFile   string
+	preParamOutput string
+	keystoreFile   string
 )
 
+// keystorePassphraseEnv is the environment variable we read the keystore passphrase from, we prompt for it if
+// it is not set
+const keystorePassphraseEnv = "TSS_KEYSTORE_PASSPHRASE"
+
 func main() {
 	// Parse the cli into configuration structs
 	tssConf, p2pConf := parseFlags()
@@ -47,17 +61,24 @@ func main() {
 	if os.Getenv("NET") == "testnet" || os.Getenv("NET") == "mocknet" {
 		types.Network = types.TestNetwork
 	}
-	// Read stdin for the private key
-	inBuf := bufio.NewReader(os.Stdin)
-	priKeyBytes, err := input.GetPassword("input node secret key:", inBuf)
-	if err != nil {
-		fmt.Printf("error in get the secret key: %s\n", err.Error())
-		return
-	}
-	priKey, err := conversion.GetPriKey(priKeyBytes)
+	priKey, err := getPriKey()
 	if err != nil {
 		log.Fatal(err)
 	}
+	// the pre-parameters generated on demand are reused on restart
+	if len(preParamFile) == 0 && len(preParamOutput) != 0 {
+		if _, err := os.Stat(preParamOutput); err == nil {
+			preParamFile = preParamOutput
+		}
+	}
+	var preParams *bkeygen.LocalPreParams
+	if len(preParamFile) != 0 {
+		loaded, err := keygen.LoadPreParamsFromFile(preParamFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		preParams = loaded[0]
+	}
 	// init tss module
 	tss, err := tss.NewTss(
 		addr.AddrList(p2pConf.BootstrapPeers),
@@ -66,13 +87,19 @@ func main() {
 		p2pConf.RendezvousString,
 		baseFolder,
 		tssConf,
-		nil,
+		preParams,
 		p2pConf.ExternalIP,
 	)
 	if nil != err {
 		log.Fatal(err)
 	}
+	if len(preParamOutput) != 0 {
+		tss.SetPreParamOutput(preParamOutput)
+	}
 	s := NewTssHttpServer(tssAddr, tss)
+	if len(tssConf.UnixSocketPath) != 0 {
+		s.SetUnixSocket(tssConf.UnixSocketPath)
+	}
 	go func() {
 		if err := s.Start(); err != nil {
 			fmt.Println(err)
@@ -88,16 +115,33 @@ func main() {
 // parseFlags - Parses the cli flags
 func parseFlags() (tssConf common.TssConfig, p2pConf p2p.Config) {
 	// we setup the configure for the general configuration
-	flag.StringVar(&tssAddr, "tss-port", "127.0.0.1:8080", "tss port")
+	flag.StringVar(&tssAddr, "tss-port", "127.0.0.1:8080", "tss port, set it to empty to only serve on the unix socket")
+	flag.StringVar(&tssConf.UnixSocketPath, "tss-socket", "", "unix socket to serve the API on")
 	flag.BoolVar(&help, "h", false, "Display Help")
 	flag.StringVar(&logLevel, "loglevel", "info", "Log Level")
 	flag.BoolVar(&pretty, "pretty-log", false, "Enables unstructured prettified logging. This is useful for local debugging")
 	flag.StringVar(&baseFolder, "home", "", "home folder to store the keygen state file")
+	flag.StringVar(&keystoreFile, "keystore", "", "load the node secret key from the given encrypted keystore file instead of stdin")
+	flag.StringVar(&preParamFile, "preparam-file", "", "use the first pre-parameter in the given file instead of generating one")
+	flag.StringVar(&preParamOutput, "preparam-output", "", "file to persist the pre-parameters generated on demand, default to "+tss.GeneratedPreParamFile+" under the home folder")
 
 	// we setup the Tss parameter configuration
-	flag.DurationVar(&tssConf.KeyGenTimeout, "gentimeout", 30*time.Second, "keygen timeout")
-	flag.DurationVar(&tssConf.KeySignTimeout, "signtimeout", 30*time.Second, "keysign timeout")
+	flag.DurationVar(&tssConf.JoinPartyTimeout, "join-party-timeout", 10*time.Second, "how long do we wait for the peers to join the party")
+	flag.DurationVar(&tssConf.ProtocolTimeout, "protocol-timeout", 30*time.Second, "timeout of the keygen/keysign rounds once the party is formed")
+	flag.DurationVar(&tssConf.KeyGenTimeout, "gentimeout", 0, "keygen timeout, override the protocol timeout if set")
+	flag.DurationVar(&tssConf.KeySignTimeout, "signtimeout", 0, "keysign timeout, override the protocol timeout if set")
 	flag.DurationVar(&tssConf.PreParamTimeout, "preparamtimeout", 5*time.Minute, "pre-parameter generation timeout")
+	flag.DurationVar(&tssConf.MaxCeremonyDuration, "max-ceremony-duration", 0, "hard deadline of the whole keygen/keysign including the join party, 0 means no deadline")
+	flag.IntVar(&tssConf.MaxQueuedMessages, "max-queued-msgs", common.DefaultMaxQueuedMessages, "maximum number of inbound messages queued for a ceremony")
+	flag.BoolVar(&tssConf.RequireSignedControlMsg, "require-signed-control-msg", false, "reject the share requests that are not signed by the sender")
+	flag.IntVar(&tssConf.JoinPartyConcurrency, "join-party-concurrency", p2p.DefaultJoinPartyConcurrency, "maximum number of join party requests sent at the same time")
+	flag.IntVar(&tssConf.ShareRequestRetries, "share-request-retries", 3, "how many more times we request a missing share that all the parties confirmed")
+	flag.DurationVar(&tssConf.ShareRequestInterval, "share-request-interval", common.DefaultShareRequestInterval, "how long we wait for a requested share before we request it again")
+	flag.BoolVar(&tssConf.DisableBlame, "disable-blame", false, "disable the blame attribution, only for the trusted deployments")
+	flag.BoolVar(&tssConf.RetainTssErrors, "retain-tss-errors", false, "attach the raw tss-lib error to the blamed nodes")
+	flag.UintVar(&tssConf.TssMaxPayload, "tss-max-payload", p2p.MaxPayload, "max payload in bytes of the keygen/keysign messages")
+	flag.UintVar(&tssConf.JoinPartyMaxPayload, "join-party-max-payload", p2p.DefaultJoinPartyMaxPayload, "max payload in bytes of the join party requests")
+	flag.StringVar(&tssConf.DeadLetterFile, "dead-letter-file", "", "record the inbound messages that fail to be processed to the given file")
 
 	// we setup the p2p network configuration
 	flag.StringVar(&p2pConf.RendezvousString, "rendezvous", "Asgard",
@@ -105,6 +149,41 @@ func parseFlags() (tssConf common.TssConfig, p2pConf p2p.Config) {
 	flag.IntVar(&p2pConf.Port, "p2p-port", 6668, "listening port local")
 	flag.StringVar(&p2pConf.ExternalIP, "external-ip", "", "external IP of this node")
 	flag.Var(&p2pConf.BootstrapPeers, "peer", "Adds a peer multiaddress to the bootstrap list")
+	flag.StringVar(&announceAllow, "announce-allow", "", "comma separated CIDRs of the addresses we announce, default to all")
+	flag.StringVar(&announceDeny, "announce-deny", "", "comma separated CIDRs of the addresses we never announce")
 	flag.Parse()
+	tssConf.AnnounceAllowCIDRs = splitCIDRs(announceAllow)
+	tssConf.AnnounceDenyCIDRs = splitCIDRs(announceDeny)
 	return
 }
+
+func splitCIDRs(value string) []string {
+	var result []string
+	for _, el := range strings.Split(value, ",") {
+		if el = strings.TrimSpace(el); len(el) != 0 {
+			result = append(result, el)
+		}
+	}
+	return result
+}
+
+// getPriKey read the node secret key from stdin, or decrypt it from the keystore file if one is given
+func getPriKey() (tcrypto.PrivKey, error) {
+	inBuf := bufio.NewReader(os.Stdin)
+	if len(keystoreFile) != 0 {
+		passphrase := os.Getenv(keystorePassphraseEnv)
+		if len(passphrase) == 0 {
+			var err error
+			passphrase, err = input.GetPassword("input keystore passphrase:", inBuf)
+			if err != nil {
+				return nil, fmt.Errorf("fail to get the keystore passphrase: %w", err)
+			}
+		}
+		return conversion.LoadKeystore(keystoreFile, passphrase)
+	}
+	priKeyBytes, err := input.GetPassword("input node secret key:", inBuf)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the secret key: %w", err)
+	}
+	return conversion.GetPriKey(priKeyBytes)
+}
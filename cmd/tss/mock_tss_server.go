@@ -12,9 +12,13 @@ import (
 )
 
 type MockTssServer struct {
-	failToStart   bool
-	failToKeyGen  bool
-	failToKeySign bool
+	failToStart       bool
+	failToKeyGen      bool
+	failToKeySign     bool
+	failToRebroadcast bool
+	failToCancel      bool
+	failToListKeys    bool
+	failToGenerate    bool
 }
 
 func (mts *MockTssServer) Start() error {
@@ -54,3 +58,41 @@ func (mts *MockTssServer) GetStatus() common.TssStatus {
 		FailedKeySign: 0,
 	}
 }
+
+func (mts *MockTssServer) GetStats() common.TssStats {
+	return common.TssStats{
+		Uptime:      1,
+		BlameCounts: map[string]uint64{blame.TssTimeout: 1},
+	}
+}
+
+func (mts *MockTssServer) RebroadcastTaskDone(msgID string) error {
+	if mts.failToRebroadcast {
+		return errors.New("you ask for it")
+	}
+	return nil
+}
+
+func (mts *MockTssServer) CancelJoinParty(msgID string) error {
+	if mts.failToCancel {
+		return errors.New("you ask for it")
+	}
+	return nil
+}
+
+func (mts *MockTssServer) GetKeys() (common.KeyListing, error) {
+	if mts.failToListKeys {
+		return common.KeyListing{}, errors.New("you ask for it")
+	}
+	return common.KeyListing{
+		Keys: []common.KeyInfo{},
+		Hash: "whatever",
+	}, nil
+}
+
+func (mts *MockTssServer) GeneratePreParams() error {
+	if mts.failToGenerate {
+		return errors.New("you ask for it")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+type contextKey string
+
+const (
+	requestIDKey    contextKey = "request_id"
+	requestIDHeader            = "X-Request-ID"
+)
+
+// apiResponse is the envelope we wrap all the http responses with
+type apiResponse struct {
+	Data      interface{} `json:"data"`
+	Error     string      `json:"error"`
+	RequestID string      `json:"request_id"`
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// withRequestID attach a newly generated request id to the given request
+func withRequestID(r *http.Request) *http.Request {
+	ctx := context.WithValue(r.Context(), requestIDKey, newRequestID())
+	return r.WithContext(ctx)
+}
+
+// getRequestID return the request id of the given request, the handlers can be called without the middleware,
+// in that case we generate one
+func getRequestID(r *http.Request) string {
+	if requestID, ok := r.Context().Value(requestIDKey).(string); ok {
+		return requestID
+	}
+	return newRequestID()
+}
+
+func (t *TssHttpServer) requestLogger(requestID string) zerolog.Logger {
+	return t.logger.With().Str("request_id", requestID).Logger()
+}
+
+// writeResponse write the given payload and error within the response envelope
+func (t *TssHttpServer) writeResponse(w http.ResponseWriter, requestID string, status int, data interface{}, err error) {
+	logger := t.requestLogger(requestID)
+	resp := apiResponse{
+		Data:      data,
+		RequestID: requestID,
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	buf, errMarshal := json.Marshal(resp)
+	if errMarshal != nil {
+		logger.Error().Err(errMarshal).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(requestIDHeader, requestID)
+	w.WriteHeader(status)
+	if _, err := w.Write(buf); err != nil {
+		logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
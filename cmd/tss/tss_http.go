@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -17,11 +19,17 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/tss"
 )
 
+var (
+	errMethodNotAllowed = errors.New("method not allowed")
+	errInvalidRequest   = errors.New("invalid request")
+)
+
 // TssHttpServer provide http endpoint for tss server
 type TssHttpServer struct {
-	logger    zerolog.Logger
-	tssServer tss.Server
-	s         *http.Server
+	logger     zerolog.Logger
+	tssServer  tss.Server
+	s          *http.Server
+	socketPath string
 }
 
 // NewTssHttpServer should only listen to the loopback
@@ -44,120 +52,249 @@ func (t *TssHttpServer) tssNewHandler() http.Handler {
 	router.Handle("/keygen", http.HandlerFunc(t.keygenHandler)).Methods(http.MethodPost)
 	router.Handle("/keysign", http.HandlerFunc(t.keySignHandler)).Methods(http.MethodPost)
 	router.Handle("/status", http.HandlerFunc(t.getNodeStatusHandler)).Methods(http.MethodGet)
+	router.Handle("/stats", http.HandlerFunc(t.getNodeStatsHandler)).Methods(http.MethodGet)
+	router.Handle("/keys", http.HandlerFunc(t.getKeysHandler)).Methods(http.MethodGet)
 	router.Handle("/ping", http.HandlerFunc(t.pingHandler)).Methods(http.MethodGet)
 	router.Handle("/p2pid", http.HandlerFunc(t.getP2pIDHandler)).Methods(http.MethodGet)
+	router.Handle("/taskdone", http.HandlerFunc(t.taskDoneHandler)).Methods(http.MethodPost)
+	router.Handle("/cancel", http.HandlerFunc(t.cancelHandler)).Methods(http.MethodPost)
+	router.Handle("/preparams/generate", http.HandlerFunc(t.generatePreParamsHandler)).Methods(http.MethodPost)
 	router.Use(logMiddleware())
 	return router
 }
 
 func (t *TssHttpServer) keygenHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	logger := t.requestLogger(requestID)
 	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
+		t.writeResponse(w, requestID, http.StatusMethodNotAllowed, nil, errMethodNotAllowed)
 		return
 	}
 	defer func() {
 		if err := r.Body.Close(); nil != err {
-			t.logger.Error().Err(err).Msg("fail to close request body")
+			logger.Error().Err(err).Msg("fail to close request body")
 		}
 	}()
-	t.logger.Info().Msg("receive key gen request")
+	logger.Info().Msg("receive key gen request")
 	decoder := json.NewDecoder(r.Body)
 	var keygenReq keygen.Request
 	if err := decoder.Decode(&keygenReq); nil != err {
-		t.logger.Error().Err(err).Msg("fail to decode keygen request")
-		w.WriteHeader(http.StatusBadRequest)
+		logger.Error().Err(err).Msg("fail to decode keygen request")
+		t.writeResponse(w, requestID, http.StatusBadRequest, nil, errInvalidRequest)
 		return
 	}
 
 	resp, err := t.tssServer.Keygen(keygenReq)
 	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to key gen")
-	}
-	t.logger.Debug().Msgf("resp:%+v", resp)
-	buf, err := json.Marshal(resp)
-	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to marshal response to json")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	_, err = w.Write(buf)
-	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to write to response")
+		logger.Error().Err(err).Msg("fail to key gen")
 	}
+	logger.Debug().Msgf("resp:%+v", resp)
+	t.writeResponse(w, requestID, http.StatusOK, resp, err)
 }
 
 func (t *TssHttpServer) keySignHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	logger := t.requestLogger(requestID)
 	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
+		t.writeResponse(w, requestID, http.StatusMethodNotAllowed, nil, errMethodNotAllowed)
 		return
 	}
 	defer func() {
 		if err := r.Body.Close(); nil != err {
-			t.logger.Error().Err(err).Msg("fail to close request body")
+			logger.Error().Err(err).Msg("fail to close request body")
 		}
 	}()
-	t.logger.Info().Msg("receive key sign request")
+	logger.Info().Msg("receive key sign request")
 
 	var keySignReq keysign.Request
 	decoder := json.NewDecoder(r.Body)
 	if err := decoder.Decode(&keySignReq); nil != err {
-		t.logger.Error().Err(err).Msg("fail to decode key sign request")
-		w.WriteHeader(http.StatusBadRequest)
+		logger.Error().Err(err).Msg("fail to decode key sign request")
+		t.writeResponse(w, requestID, http.StatusBadRequest, nil, errInvalidRequest)
 		return
 	}
-	t.logger.Info().Msgf("request:%+v", keySignReq)
+	logger.Info().Msgf("request:%+v", keySignReq)
 	signResp, err := t.tssServer.KeySign(keySignReq)
 	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to key sign")
-		w.WriteHeader(http.StatusInternalServerError)
+		logger.Error().Err(err).Msg("fail to key sign")
+		t.writeResponse(w, requestID, http.StatusInternalServerError, nil, err)
 		return
 	}
+	t.writeResponse(w, requestID, http.StatusOK, signResp, nil)
+}
 
-	jsonResult, err := json.MarshalIndent(signResp, "", "	")
-	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to marshal response to json message")
-		w.WriteHeader(http.StatusInternalServerError)
+// messageIDRequest identifies the ceremony the taskdone/cancel request refers to
+type messageIDRequest struct {
+	MessageID string `json:"message_id"`
+}
+
+func (t *TssHttpServer) taskDoneHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	logger := t.requestLogger(requestID)
+	if r.Method != http.MethodPost {
+		t.writeResponse(w, requestID, http.StatusMethodNotAllowed, nil, errMethodNotAllowed)
 		return
 	}
-	_, err = w.Write(jsonResult)
-	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to write response")
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	var req messageIDRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&req); nil != err || len(req.MessageID) == 0 {
+		logger.Error().Err(err).Msg("fail to decode task done request")
+		t.writeResponse(w, requestID, http.StatusBadRequest, nil, errInvalidRequest)
+		return
+	}
+	if err := t.tssServer.RebroadcastTaskDone(req.MessageID); err != nil {
+		logger.Error().Err(err).Msg("fail to re-broadcast the task done")
+		t.writeResponse(w, requestID, http.StatusNotFound, nil, err)
+		return
+	}
+	t.writeResponse(w, requestID, http.StatusOK, nil, nil)
+}
+
+func (t *TssHttpServer) cancelHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	logger := t.requestLogger(requestID)
+	if r.Method != http.MethodPost {
+		t.writeResponse(w, requestID, http.StatusMethodNotAllowed, nil, errMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	var req messageIDRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&req); nil != err || len(req.MessageID) == 0 {
+		logger.Error().Err(err).Msg("fail to decode cancel request")
+		t.writeResponse(w, requestID, http.StatusBadRequest, nil, errInvalidRequest)
+		return
+	}
+	if err := t.tssServer.CancelJoinParty(req.MessageID); err != nil {
+		logger.Error().Err(err).Msg("fail to cancel the join party")
+		t.writeResponse(w, requestID, http.StatusNotFound, nil, err)
+		return
+	}
+	t.writeResponse(w, requestID, http.StatusOK, nil, nil)
+}
+
+// generatePreParamsHandler generate new pre-parameters, it returns once the generation is done
+func (t *TssHttpServer) generatePreParamsHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	logger := t.requestLogger(requestID)
+	if r.Method != http.MethodPost {
+		t.writeResponse(w, requestID, http.StatusMethodNotAllowed, nil, errMethodNotAllowed)
+		return
+	}
+	if err := t.tssServer.GeneratePreParams(); err != nil {
+		logger.Error().Err(err).Msg("fail to generate the pre-parameters")
+		status := http.StatusInternalServerError
+		if errors.Is(err, tss.ErrPreParamsGenerating) {
+			status = http.StatusConflict
+		}
+		t.writeResponse(w, requestID, status, nil, err)
+		return
 	}
+	t.writeResponse(w, requestID, http.StatusOK, nil, nil)
+}
+
+func (t *TssHttpServer) getNodeStatusHandler(w http.ResponseWriter, r *http.Request) {
+	t.writeResponse(w, getRequestID(r), http.StatusOK, t.tssServer.GetStatus(), nil)
+}
+
+func (t *TssHttpServer) getNodeStatsHandler(w http.ResponseWriter, r *http.Request) {
+	t.writeResponse(w, getRequestID(r), http.StatusOK, t.tssServer.GetStats(), nil)
 }
 
-func (t *TssHttpServer) getNodeStatusHandler(w http.ResponseWriter, _ *http.Request) {
-	buf, err := json.Marshal(t.tssServer.GetStatus())
+func (t *TssHttpServer) getKeysHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := getRequestID(r)
+	keys, err := t.tssServer.GetKeys()
 	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to marshal response to json")
-		w.WriteHeader(http.StatusInternalServerError)
+		t.requestLogger(requestID).Error().Err(err).Msg("fail to list the keys")
+		t.writeResponse(w, requestID, http.StatusInternalServerError, nil, err)
 		return
 	}
-	_, err = w.Write(buf)
+	t.writeResponse(w, requestID, http.StatusOK, keys, nil)
+}
+
+// SetUnixSocket serve the API on the given unix socket as well, if the tss address is empty we only listen to the
+// unix socket
+func (t *TssHttpServer) SetUnixSocket(path string) {
+	t.socketPath = path
+}
+
+// listenUnixSocket listen to the unix socket that only the owner of the process can access
+func listenUnixSocket(path string) (net.Listener, error) {
+	// remove the socket left behind by a previous run
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("fail to remove the stale unix socket: %w", err)
+		}
+	}
+	// the socket is created with the 0600 permission straight away, setting it after the bind leaves a window in
+	// which other local users can connect to it
+	oldMask := setUmask(0177)
+	l, err := net.Listen("unix", path)
+	setUmask(oldMask)
 	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to write to response")
+		return nil, fmt.Errorf("fail to listen to the unix socket: %w", err)
 	}
+	return l, nil
 }
 
 func (t *TssHttpServer) Start() error {
 	if t.s == nil {
 		return errors.New("invalid http server instance")
 	}
+	if len(t.s.Addr) == 0 && len(t.socketPath) == 0 {
+		return errors.New("neither tss address nor unix socket is given")
+	}
 	if err := t.tssServer.Start(); err != nil {
 		return fmt.Errorf("fail to start tss server: %w", err)
 	}
-	if err := t.s.ListenAndServe(); err != nil {
-		if err != http.ErrServerClosed {
+	var listeners []net.Listener
+	if len(t.socketPath) != 0 {
+		l, err := listenUnixSocket(t.socketPath)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+	if len(t.s.Addr) != 0 {
+		l, err := net.Listen("tcp", t.s.Addr)
+		if err != nil {
+			for _, el := range listeners {
+				_ = el.Close()
+			}
 			return fmt.Errorf("fail to start http server: %w", err)
 		}
+		listeners = append(listeners, l)
 	}
-
-	return nil
+	errChan := make(chan error, len(listeners))
+	for _, el := range listeners {
+		go func(l net.Listener) {
+			errChan <- t.s.Serve(l)
+		}(el)
+	}
+	var result error
+	for range listeners {
+		if err := <-errChan; err != nil && err != http.ErrServerClosed && result == nil {
+			result = fmt.Errorf("fail to start http server: %w", err)
+		}
+	}
+	return result
 }
 
 func logMiddleware() mux.MiddlewareFunc {
 	return func(handler http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = withRequestID(r)
 			log.Debug().
+				Str("request_id", getRequestID(r)).
 				Str("route", r.URL.Path).
 				Str("port", r.URL.Port()).
 				Str("method", r.Method).
@@ -179,14 +316,10 @@ func (t *TssHttpServer) Stop() error {
 	return err
 }
 
-func (t *TssHttpServer) pingHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusOK)
+func (t *TssHttpServer) pingHandler(w http.ResponseWriter, r *http.Request) {
+	t.writeResponse(w, getRequestID(r), http.StatusOK, nil, nil)
 }
 
-func (t *TssHttpServer) getP2pIDHandler(w http.ResponseWriter, _ *http.Request) {
-	localPeerID := t.tssServer.GetLocalPeerID()
-	_, err := w.Write([]byte(localPeerID))
-	if err != nil {
-		t.logger.Error().Err(err).Msg("fail to write to response")
-	}
+func (t *TssHttpServer) getP2pIDHandler(w http.ResponseWriter, r *http.Request) {
+	t.writeResponse(w, getRequestID(r), http.StatusOK, t.tssServer.GetLocalPeerID(), nil)
 }
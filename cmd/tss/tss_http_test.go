@@ -2,9 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -22,6 +26,23 @@ type TssHttpServerTestSuite struct {
 
 var _ = Suite(&TssHttpServerTestSuite{})
 
+type testResponse struct {
+	Data      json.RawMessage `json:"data"`
+	Error     string          `json:"error"`
+	RequestID string          `json:"request_id"`
+}
+
+// decodeResponse decode the response envelope, and the payload into the given data if it is not nil
+func decodeResponse(c *C, w *httptest.ResponseRecorder, data interface{}) testResponse {
+	var resp testResponse
+	c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+	c.Assert(resp.RequestID, Equals, w.Header().Get(requestIDHeader))
+	if data != nil {
+		c.Assert(json.Unmarshal(resp.Data, data), IsNil)
+	}
+	return resp
+}
+
 func (TssHttpServerTestSuite) TestNewTssHttpServer(c *C) {
 	tssServer := &MockTssServer{}
 	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
@@ -39,6 +60,41 @@ func (TssHttpServerTestSuite) TestNewTssHttpServer(c *C) {
 	c.Assert(s.Start(), NotNil)
 }
 
+func (TssHttpServerTestSuite) TestUnixSocket(c *C) {
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("", tssServer)
+	c.Assert(s.Start(), NotNil)
+	socketPath := filepath.Join(c.MkDir(), "tss.sock")
+	s.SetUnixSocket(socketPath)
+	// the socket must not depend on the umask of the process
+	oldMask := setUmask(0)
+	defer setUmask(oldMask)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.Assert(s.Start(), IsNil)
+	}()
+	time.Sleep(time.Second)
+	fi, err := os.Stat(socketPath)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Mode().Perm(), Equals, os.FileMode(0600))
+
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix/ping")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(s.Stop(), IsNil)
+	wg.Wait()
+}
+
 func (TssHttpServerTestSuite) TestPingHandler(c *C) {
 	tssServer := &MockTssServer{}
 	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
@@ -47,6 +103,8 @@ func (TssHttpServerTestSuite) TestPingHandler(c *C) {
 	res := httptest.NewRecorder()
 	s.pingHandler(res, req)
 	c.Assert(res.Code, Equals, http.StatusOK)
+	resp := decodeResponse(c, res, nil)
+	c.Assert(resp.RequestID, Not(Equals), "")
 }
 
 func (TssHttpServerTestSuite) TestGetP2pIDHandler(c *C) {
@@ -57,6 +115,9 @@ func (TssHttpServerTestSuite) TestGetP2pIDHandler(c *C) {
 	res := httptest.NewRecorder()
 	s.getP2pIDHandler(res, req)
 	c.Assert(res.Code, Equals, http.StatusOK)
+	var peerID string
+	decodeResponse(c, res, &peerID)
+	c.Assert(peerID, Not(Equals), "")
 }
 
 func (TssHttpServerTestSuite) TestGetNodeStatusHandler(c *C) {
@@ -68,7 +129,39 @@ func (TssHttpServerTestSuite) TestGetNodeStatusHandler(c *C) {
 	s.getNodeStatusHandler(res, req)
 	c.Assert(res.Code, Equals, http.StatusOK)
 	var status common.TssStatus
-	c.Assert(json.Unmarshal(res.Body.Bytes(), &status), IsNil)
+	resp := decodeResponse(c, res, &status)
+	c.Assert(resp.Error, Equals, "")
+}
+
+func (TssHttpServerTestSuite) TestGetNodeStatsHandler(c *C) {
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+	res := httptest.NewRecorder()
+	s.getNodeStatsHandler(res, req)
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var stats common.TssStats
+	decodeResponse(c, res, &stats)
+	c.Assert(stats.BlameCounts, HasLen, 1)
+}
+
+func (TssHttpServerTestSuite) TestGetKeysHandler(c *C) {
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	req := httptest.NewRequest(http.MethodGet, "/keys", nil)
+	res := httptest.NewRecorder()
+	s.getKeysHandler(res, req)
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var keys common.KeyListing
+	decodeResponse(c, res, &keys)
+	c.Assert(keys.Hash, Equals, "whatever")
+
+	tssServer.failToListKeys = true
+	res = httptest.NewRecorder()
+	s.getKeysHandler(res, req)
+	c.Assert(res.Code, Equals, http.StatusInternalServerError)
 }
 
 func (TssHttpServerTestSuite) TestKeygenHandler(c *C) {
@@ -108,6 +201,8 @@ func (TssHttpServerTestSuite) TestKeygenHandler(c *C) {
 			},
 			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
 				c.Assert(w.Code, Equals, http.StatusOK)
+				resp := decodeResponse(c, w, nil)
+				c.Assert(resp.Error, Not(Equals), "")
 			},
 		},
 		{
@@ -120,7 +215,7 @@ func (TssHttpServerTestSuite) TestKeygenHandler(c *C) {
 			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
 				c.Assert(w.Code, Equals, http.StatusOK)
 				var resp keygen.Response
-				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+				decodeResponse(c, w, &resp)
 			},
 		},
 	}
@@ -197,7 +292,7 @@ func (TssHttpServerTestSuite) TestKeysignHandler(c *C) {
 			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
 				c.Assert(w.Code, Equals, http.StatusOK)
 				var resp keygen.Response
-				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+				decodeResponse(c, w, &resp)
 			},
 		},
 	}
@@ -215,3 +310,180 @@ func (TssHttpServerTestSuite) TestKeysignHandler(c *C) {
 		tc.resultChecker(c, res)
 	}
 }
+
+func (TssHttpServerTestSuite) TestTaskDoneHandler(c *C) {
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "method get should return status method not allowed",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/taskdone", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+			},
+		},
+		{
+			name: "empty message id should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/taskdone", bytes.NewBufferString(`{}`))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "unknown ceremony should return status not found",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/taskdone", bytes.NewBufferString(`{"message_id":"whatever"}`))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToRebroadcast = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusNotFound)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/taskdone", bytes.NewBufferString(`{"message_id":"whatever"}`))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.taskDoneHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestCancelHandler(c *C) {
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "method get should return status method not allowed",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/cancel", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+			},
+		},
+		{
+			name: "empty message id should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/cancel", bytes.NewBufferString(`{}`))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "unknown join party should return status not found",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/cancel", bytes.NewBufferString(`{"message_id":"whatever"}`))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToCancel = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusNotFound)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/cancel", bytes.NewBufferString(`{"message_id":"whatever"}`))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.cancelHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestGeneratePreParamsHandler(c *C) {
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "method get should return status method not allowed",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/preparams/generate", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+			},
+		},
+		{
+			name: "fail to generate should return internal server error",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/preparams/generate", nil)
+			},
+			setter: func(s *MockTssServer) {
+				s.failToGenerate = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/preparams/generate", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.generatePreParamsHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"syscall"
+)
+
+// setUmask set the file mode creation mask of the process and return the previous one
+func setUmask(mask int) int {
+	return syscall.Umask(mask)
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package main
+
+// setUmask is not supported on windows, the unix socket keeps the default permission
+func setUmask(_ int) int {
+	return 0
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+var (
+	keygenRounds = []string{
+		messages.KEYGEN1, messages.KEYGEN2aUnicast, messages.KEYGEN2b, messages.KEYGEN3,
+	}
+	keysignRounds = []string{
+		messages.KEYSIGN1aUnicast, messages.KEYSIGN1b, messages.KEYSIGN2Unicast, messages.KEYSIGN3, messages.KEYSIGN4,
+		messages.KEYSIGN5, messages.KEYSIGN6, messages.KEYSIGN7, messages.KEYSIGN8, messages.KEYSIGN9,
+	}
+)
+
+// errUnsignedControlMsg is returned when we require the share requests to be signed and the request has no signature
+var errUnsignedControlMsg = errors.New("share request is not signed")
+
+// controlMsgSignBytes return the bytes the requester signs, the message body is not included as the responder fills it in
+func controlMsgSignBytes(msg *messages.TssControl) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(msg.ReqHash)
+	buf.WriteString(msg.ReqKey)
+	buf.WriteString(msg.RequestType.String())
+	return buf.Bytes()
+}
+
+func (t *TssCommon) signControlMsg(msg *messages.TssControl) error {
+	sig, err := generateSignature(controlMsgSignBytes(msg), t.msgID, t.privateKey)
+	if err != nil {
+		return fmt.Errorf("fail to sign the share request: %w", err)
+	}
+	msg.Sig = sig
+	return nil
+}
+
+// getPartyIDOfPeer return the party id of the committee member with the given peer id
+func (t *TssCommon) getPartyIDOfPeer(peerID peer.ID) (string, bool) {
+	for partyID, el := range t.PartyIDtoP2PID {
+		if el == peerID {
+			return partyID, true
+		}
+	}
+	return "", false
+}
+
+func isValidRound(msgType messages.THORChainTSSMessageType, round string) bool {
+	var rounds []string
+	switch msgType {
+	case messages.TSSKeyGenMsg:
+		rounds = keygenRounds
+	case messages.TSSKeySignMsg:
+		rounds = keysignRounds
+	default:
+		return false
+	}
+	for _, el := range rounds {
+		if strings.HasSuffix(round, el) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateShareRequest check the share request comes from a committee member and asks for a share of a valid
+// round that the requester is entitled to. The signature is always checked if present, the unsigned requests are
+// only rejected if RequireSignedControlMsg is set. It returns whether the request is signed by the requester
+func (t *TssCommon) validateShareRequest(msg *messages.TssControl, peerID peer.ID) (bool, error) {
+	partyInfo := t.getPartyInfo()
+	if partyInfo == nil {
+		return false, errors.New("local party is not ready")
+	}
+	requesterPartyID, ok := t.getPartyIDOfPeer(peerID)
+	if !ok {
+		return false, fmt.Errorf("peer %s is not in the committee", peerID)
+	}
+	signed := false
+	if len(msg.Sig) != 0 {
+		requester, ok := partyInfo.PartyIDMap[requesterPartyID]
+		if !ok {
+			return false, fmt.Errorf("fail to find the party of peer %s", peerID)
+		}
+		var pk secp256k1.PubKeySecp256k1
+		copy(pk[:], requester.GetKey())
+		if !verifySignature(pk, controlMsgSignBytes(msg), msg.Sig, t.msgID) {
+			return false, errors.New("invalid share request signature")
+		}
+		signed = true
+	} else if t.conf.RequireSignedControlMsg {
+		return false, errUnsignedControlMsg
+	}
+
+	items := strings.SplitN(msg.ReqKey, "-", 2)
+	if len(items) != 2 {
+		return signed, fmt.Errorf("invalid request key %s", msg.ReqKey)
+	}
+	ownerPartyID, round := items[0], items[1]
+	if _, ok := partyInfo.PartyIDMap[ownerPartyID]; !ok {
+		return signed, fmt.Errorf("request the share of unknown party %s", ownerPartyID)
+	}
+	if ownerPartyID == requesterPartyID {
+		return signed, errors.New("request its own share")
+	}
+	if !isValidRound(msg.RequestType, round) {
+		return signed, fmt.Errorf("request the share of invalid round %s", round)
+	}
+	// the unicast share is only for its receivers
+	storedMsg := t.blameMgr.GetRoundMgr().Get(msg.ReqKey)
+	if storedMsg != nil && storedMsg.Routing != nil && !storedMsg.Routing.IsBroadcast {
+		for _, el := range storedMsg.Routing.To {
+			if el.Id == requesterPartyID {
+				return signed, nil
+			}
+		}
+		return signed, fmt.Errorf("request the unicast share of round %s sent to others", round)
+	}
+	return signed, nil
+}
+
+// validateShareResponse check the share we receive is the one we requested
+func validateShareResponse(msg *messages.TssControl) error {
+	if msg.Msg.Routing == nil || msg.Msg.Routing.From == nil {
+		return errors.New("invalid share in the response")
+	}
+	if msg.Msg.GetCacheKey() != msg.ReqKey {
+		return fmt.Errorf("share of %s does not match the request key %s", msg.Msg.GetCacheKey(), msg.ReqKey)
+	}
+	hash, err := conversion.BytesToHashString(msg.Msg.Message)
+	if err != nil {
+		return fmt.Errorf("fail to calculate hash of the share: %w", err)
+	}
+	if hash != msg.ReqHash {
+		return errors.New("share does not match the requested hash")
+	}
+	return nil
+}
+
+// blameShareRequest report the committee member who signed an invalid share request with the blame of the ceremony,
+// the signed request is kept as the proof
+func (t *TssCommon) blameShareRequest(msg *messages.TssControl, peerID peer.ID) {
+	pubKey, err := conversion.GetPubKeyFromPeerID(peerID.String())
+	if err != nil {
+		t.logger.Error().Err(err).Msgf("fail to get the pub key of peer %s", peerID)
+		return
+	}
+	node := blame.NewNode(pubKey, controlMsgSignBytes(msg), msg.Sig)
+	node.Category = blame.CategoryOfReason(blame.InvalidControlMsg)
+	t.blameMgr.GetBlame().AddBlameNodes(node)
+}
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"fmt"
+
+	btss "github.com/binance-chain/tss-lib/tss"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+func (t *TssTestSuite) TestValidateShareRequest(c *C) {
+	tssCommonStruct, _, partiesID := setupProcessVerMsgEnv(c, t.privKey, testBlamePubKeys, 4)
+	sender := findSender(partiesID)
+	c.Assert(sender, NotNil)
+	senderPeer, err := conversion.GetPeerIDFromPartyID(sender)
+	c.Assert(err, IsNil)
+	var owner, other *btss.PartyID
+	for _, el := range partiesID {
+		if el.Id == sender.Id {
+			continue
+		}
+		if owner == nil {
+			owner = el
+		} else if other == nil {
+			other = el
+		}
+	}
+	round := "binance.tss-lib.ecdsa.keygen." + messages.KEYGEN1
+	newRequest := func(ownerID, round string) *messages.TssControl {
+		return &messages.TssControl{
+			ReqHash:     "hash",
+			ReqKey:      fmt.Sprintf("%s-%s", ownerID, round),
+			RequestType: messages.TSSKeyGenMsg,
+		}
+	}
+
+	signed, err := tssCommonStruct.validateShareRequest(newRequest(owner.Id, round), senderPeer)
+	c.Assert(err, IsNil)
+	c.Assert(signed, Equals, false)
+	// the peer is not in the committee
+	_, err = tssCommonStruct.validateShareRequest(newRequest(owner.Id, round), conversion.GetRandomPeerID())
+	c.Assert(err, NotNil)
+	// its own share
+	_, err = tssCommonStruct.validateShareRequest(newRequest(sender.Id, round), senderPeer)
+	c.Assert(err, NotNil)
+	// unknown party and invalid round
+	_, err = tssCommonStruct.validateShareRequest(newRequest("unknown", round), senderPeer)
+	c.Assert(err, NotNil)
+	_, err = tssCommonStruct.validateShareRequest(newRequest(owner.Id, "invalid"), senderPeer)
+	c.Assert(err, NotNil)
+	_, err = tssCommonStruct.validateShareRequest(&messages.TssControl{ReqKey: "invalid", RequestType: messages.TSSKeyGenMsg}, senderPeer)
+	c.Assert(err, NotNil)
+	keysignRequest := newRequest(owner.Id, round)
+	keysignRequest.RequestType = messages.TSSKeySignMsg
+	_, err = tssCommonStruct.validateShareRequest(keysignRequest, senderPeer)
+	c.Assert(err, NotNil)
+
+	// the unicast share is only for its receivers
+	unicastRound := "binance.tss-lib.ecdsa.keygen." + messages.KEYGEN2aUnicast
+	unicastKey := fmt.Sprintf("%s-%s", owner.Id, unicastRound)
+	tssCommonStruct.blameMgr.GetRoundMgr().Set(unicastKey, &messages.WireMessage{
+		Routing: &btss.MessageRouting{
+			From:        owner,
+			To:          []*btss.PartyID{other},
+			IsBroadcast: false,
+		},
+		RoundInfo: unicastRound,
+	})
+	_, err = tssCommonStruct.validateShareRequest(newRequest(owner.Id, unicastRound), senderPeer)
+	c.Assert(err, NotNil)
+
+	// the test private key belongs to the sender
+	req := newRequest(owner.Id, round)
+	c.Assert(tssCommonStruct.signControlMsg(req), IsNil)
+	signed, err = tssCommonStruct.validateShareRequest(req, senderPeer)
+	c.Assert(err, IsNil)
+	c.Assert(signed, Equals, true)
+	// the request is changed after it is signed
+	req.ReqKey = fmt.Sprintf("%s-%s", other.Id, round)
+	signed, err = tssCommonStruct.validateShareRequest(req, senderPeer)
+	c.Assert(err, ErrorMatches, "invalid share request signature")
+	c.Assert(signed, Equals, false)
+
+	// the signed request of the share the sender is not entitled to is blamed
+	req = newRequest(owner.Id, unicastRound)
+	c.Assert(tssCommonStruct.signControlMsg(req), IsNil)
+	signed, err = tssCommonStruct.validateShareRequest(req, senderPeer)
+	c.Assert(err, NotNil)
+	c.Assert(signed, Equals, true)
+	tssCommonStruct.blameShareRequest(req, senderPeer)
+	blameNodes := tssCommonStruct.blameMgr.GetBlame().BlameNodes
+	c.Assert(blameNodes, HasLen, 1)
+	c.Assert(blameNodes[0].Pubkey, Equals, testSenderPubKey)
+	c.Assert(blameNodes[0].Category, Equals, blame.CategoryMalicious)
+
+	tssCommonStruct.conf.RequireSignedControlMsg = true
+	_, err = tssCommonStruct.validateShareRequest(newRequest(owner.Id, round), senderPeer)
+	c.Assert(err, Equals, errUnsignedControlMsg)
+}
+
+func (t *TssTestSuite) TestValidateShareResponse(c *C) {
+	sender := btss.NewPartyID("1", "", nil)
+	round := "binance.tss-lib.ecdsa.keygen." + messages.KEYGEN1
+	hash, err := conversion.BytesToHashString([]byte("share"))
+	c.Assert(err, IsNil)
+	resp := &messages.TssControl{
+		ReqHash:     hash,
+		ReqKey:      fmt.Sprintf("%s-%s", sender.Id, round),
+		RequestType: messages.TSSKeyGenMsg,
+		Msg: &messages.WireMessage{
+			Routing:   &btss.MessageRouting{From: sender, IsBroadcast: true},
+			RoundInfo: round,
+			Message:   []byte("share"),
+		},
+	}
+	c.Assert(validateShareResponse(resp), IsNil)
+	resp.Msg.Message = []byte("other share")
+	c.Assert(validateShareResponse(resp), NotNil)
+	resp.Msg.Message = []byte("share")
+	resp.ReqKey = "2-" + round
+	c.Assert(validateShareResponse(resp), NotNil)
+	resp.Msg.Routing = nil
+	c.Assert(validateShareResponse(resp), NotNil)
+}
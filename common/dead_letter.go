@@ -0,0 +1,78 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter is the record of an inbound message we fail to process
+type DeadLetter struct {
+	Time        time.Time `json:"time"`
+	MsgID       string    `json:"msg_id"`
+	Sender      string    `json:"sender"`
+	MessageType string    `json:"message_type,omitempty"`
+	Reason      string    `json:"reason"`
+	PayloadHash string    `json:"payload_hash,omitempty"`
+}
+
+// DeadLetterSink appends the dead letters to a file, one json object per line
+type DeadLetterSink struct {
+	lock *sync.Mutex
+	file *os.File
+}
+
+// NewDeadLetterSink create a new instance of DeadLetterSink that writes to the given file
+func NewDeadLetterSink(path string) (*DeadLetterSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open the dead letter file: %w", err)
+	}
+	return &DeadLetterSink{
+		lock: &sync.Mutex{},
+		file: f,
+	}, nil
+}
+
+// Record write the dead letter to the file, it is a no-op if the sink is nil
+func (ds *DeadLetterSink) Record(msgID, sender, msgType, reason string, payload []byte) error {
+	if ds == nil {
+		return nil
+	}
+	letter := DeadLetter{
+		Time:        time.Now().UTC(),
+		MsgID:       msgID,
+		Sender:      sender,
+		MessageType: msgType,
+		Reason:      reason,
+	}
+	if len(payload) != 0 {
+		hash, err := MsgToHashString(payload)
+		if err != nil {
+			return err
+		}
+		letter.PayloadHash = hash
+	}
+	buf, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the dead letter: %w", err)
+	}
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	if _, err := ds.file.Write(append(buf, '\n')); err != nil {
+		return fmt.Errorf("fail to write the dead letter: %w", err)
+	}
+	return nil
+}
+
+// Close the underlying file
+func (ds *DeadLetterSink) Close() error {
+	if ds == nil {
+		return nil
+	}
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	return ds.file.Close()
+}
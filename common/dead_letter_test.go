@@ -0,0 +1,56 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+type DeadLetterTestSuite struct{}
+
+var _ = Suite(&DeadLetterTestSuite{})
+
+func (DeadLetterTestSuite) TestDeadLetterSink(c *C) {
+	var nilSink *DeadLetterSink
+	c.Assert(nilSink.Record("msg1", "peer1", "", "whatever", nil), IsNil)
+	c.Assert(nilSink.Close(), IsNil)
+
+	path := filepath.Join(c.MkDir(), "dead_letters.log")
+	sink, err := NewDeadLetterSink(path)
+	c.Assert(err, IsNil)
+	c.Assert(sink.Record("msg1", "peer1", messages.TSSKeyGenMsg.String(), "bad share", []byte("payload")), IsNil)
+	c.Assert(sink.Record("msg1", "peer2", "", "fail to unmarshal", nil), IsNil)
+	c.Assert(sink.Close(), IsNil)
+
+	f, err := os.Open(path)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var letter DeadLetter
+		c.Assert(json.Unmarshal(scanner.Bytes(), &letter), IsNil)
+		letters = append(letters, letter)
+	}
+	c.Assert(letters, HasLen, 2)
+	hash, err := MsgToHashString([]byte("payload"))
+	c.Assert(err, IsNil)
+	c.Assert(letters[0].Sender, Equals, "peer1")
+	c.Assert(letters[0].Reason, Equals, "bad share")
+	c.Assert(letters[0].PayloadHash, Equals, hash)
+	c.Assert(letters[1].PayloadHash, Equals, "")
+}
+
+func (DeadLetterTestSuite) TestUnknownMessageType(c *C) {
+	tssCommon := NewTssCommon("", nil, TssConfig{}, "msg1", nil)
+	err := tssCommon.ProcessOneMessage(&messages.WrappedMessage{
+		MessageType: messages.Unknown,
+		MsgID:       "msg1",
+	}, "peer1")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,14 @@
+package common
+
+import (
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+// FaultInjector tamper with the messages of a ceremony for the chaos tests, it can only be set in the binaries built
+// with the chaos tag
+type FaultInjector interface {
+	// Inbound is called before we process the message received from the given peer, return false to drop it
+	Inbound(msg *messages.WrappedMessage, peerID string) bool
+	// Outbound is called before we send the message to the peers, return false to drop it
+	Outbound(msg *messages.BroadcastMsgChan) bool
+}
@@ -0,0 +1,10 @@
+//go:build chaos
+// +build chaos
+
+package common
+
+// SetFaultInjector set the hook that tampers with the inbound and outbound messages of this ceremony
+func (t *TssCommon) SetFaultInjector(fi FaultInjector) {
+	t.logger.Warn().Msg("fault injection is enabled, never use this binary in a real deployment")
+	t.faultInjector = fi
+}
@@ -0,0 +1,51 @@
+//go:build chaos
+// +build chaos
+
+package common
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+type FaultInjectorTestSuite struct{}
+
+var _ = Suite(&FaultInjectorTestSuite{})
+
+// dropInjector drop all the messages of the given type from the given peer, and all the outbound messages
+type dropInjector struct {
+	msgType messages.THORChainTSSMessageType
+	peerID  string
+}
+
+func (d *dropInjector) Inbound(msg *messages.WrappedMessage, peerID string) bool {
+	return msg.MessageType != d.msgType || peerID != d.peerID
+}
+
+func (d *dropInjector) Outbound(msg *messages.BroadcastMsgChan) bool {
+	return false
+}
+
+func (FaultInjectorTestSuite) TestFaultInjector(c *C) {
+	broadcastChannel := make(chan *messages.BroadcastMsgChan, 1)
+	tssCommon := NewTssCommon("", broadcastChannel, TssConfig{}, "msgID", nil)
+	tssCommon.SetFaultInjector(&dropInjector{
+		msgType: messages.TSSTaskDone,
+		peerID:  "1",
+	})
+	payload, err := json.Marshal(messages.TssTaskNotifier{TaskDone: true})
+	c.Assert(err, IsNil)
+	wrappedMsg := &messages.WrappedMessage{
+		MessageType: messages.TSSTaskDone,
+		MsgID:       "msgID",
+		Payload:     payload,
+	}
+	c.Assert(tssCommon.ProcessOneMessage(wrappedMsg, "1"), IsNil)
+	c.Assert(tssCommon.finishedPeers, HasLen, 0)
+
+	tssCommon.renderToP2P(&messages.BroadcastMsgChan{WrappedMessage: *wrappedMsg})
+	c.Assert(broadcastChannel, HasLen, 0)
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+// DefaultShareRequestInterval defines how long do we wait for the requested share before we ask again, if the
+// ShareRequestRetries is set without an interval
+const DefaultShareRequestInterval = 2 * time.Second
+
+type pendingShareRequest struct {
+	key     string
+	msg     *messages.TssControl
+	peersID []peer.ID
+	sentAt  time.Time
+	tries   int
+}
+
+// shareRequestTracker keeps the shares we have requested from the peers since all the parties confirmed the hash
+// but we never received the message
+type shareRequestTracker struct {
+	lock    *sync.Mutex
+	pending map[string]*pendingShareRequest
+}
+
+func newShareRequestTracker() *shareRequestTracker {
+	return &shareRequestTracker{
+		lock:    &sync.Mutex{},
+		pending: make(map[string]*pendingShareRequest),
+	}
+}
+
+// add start tracking the request, the request that is already tracked keeps its retry count
+func (st *shareRequestTracker) add(key string, msg *messages.TssControl, peersID []peer.ID) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	if item, ok := st.pending[key]; ok {
+		item.msg = msg
+		item.peersID = peersID
+		return
+	}
+	st.pending[key] = &pendingShareRequest{
+		key:     key,
+		msg:     msg,
+		peersID: peersID,
+		sentAt:  time.Now(),
+	}
+}
+
+func (st *shareRequestTracker) done(key string) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	delete(st.pending, key)
+}
+
+// expired return the requests that are not answered within the interval, the ones that run out of retries are dropped
+func (st *shareRequestTracker) expired(now time.Time, interval time.Duration, retries int) []*pendingShareRequest {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	var result []*pendingShareRequest
+	for key, item := range st.pending {
+		if now.Sub(item.sentAt) < interval {
+			continue
+		}
+		if item.tries >= retries {
+			delete(st.pending, key)
+			continue
+		}
+		item.tries++
+		item.sentAt = now
+		result = append(result, item)
+	}
+	return result
+}
+
+func (st *shareRequestTracker) size() int {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	return len(st.pending)
+}
+
+func (t *TssCommon) shareRequestInterval() time.Duration {
+	if t.conf.ShareRequestInterval > 0 {
+		return t.conf.ShareRequestInterval
+	}
+	return DefaultShareRequestInterval
+}
+
+// retryShareRequests ask the peers again for the shares we still miss, the ones we have received meanwhile are
+// no longer tracked
+func (t *TssCommon) retryShareRequests() {
+	for _, item := range t.shareRequests.expired(time.Now(), t.shareRequestInterval(), t.conf.ShareRequestRetries) {
+		localCacheItem := t.TryGetLocalCacheItem(item.key)
+		if localCacheItem == nil || localCacheItem.Msg != nil {
+			t.shareRequests.done(item.key)
+			continue
+		}
+		t.logger.Warn().Msgf("still missing the share of %s, request it again(%d/%d)", item.key, item.tries, t.conf.ShareRequestRetries)
+		t.blameMgr.GetShareMgr().Set(item.msg.ReqHash)
+		if err := t.processRequestMsgFromPeer(item.peersID, item.msg, true); err != nil {
+			t.logger.Error().Err(err).Msg("fail to request the share again")
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+type ShareRequestTestSuite struct{}
+
+var _ = Suite(&ShareRequestTestSuite{})
+
+func (ShareRequestTestSuite) TestShareRequestTracker(c *C) {
+	tracker := newShareRequestTracker()
+	peers := []peer.ID{conversion.GetRandomPeerID()}
+	msg := &messages.TssControl{ReqHash: "hash", ReqKey: "1-round2", RequestType: messages.TSSKeyGenMsg}
+	tracker.add("1-round2", msg, peers)
+	tracker.add("2-round2", msg, peers)
+	c.Assert(tracker.size(), Equals, 2)
+	tracker.done("2-round2")
+	c.Assert(tracker.size(), Equals, 1)
+
+	interval := time.Second
+	retries := 2
+	// not expired yet
+	c.Assert(tracker.expired(time.Now(), interval, retries), HasLen, 0)
+	now := time.Now()
+	for i := 1; i <= retries; i++ {
+		now = now.Add(interval)
+		// add the same request again does not reset the retry count
+		tracker.add("1-round2", msg, peers)
+		items := tracker.expired(now, interval, retries)
+		c.Assert(items, HasLen, 1)
+		c.Assert(items[0].key, Equals, "1-round2")
+		c.Assert(items[0].tries, Equals, i)
+	}
+	// we give up after the retries
+	c.Assert(tracker.expired(now.Add(interval), interval, retries), HasLen, 0)
+	c.Assert(tracker.size(), Equals, 0)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	btss "github.com/binance-chain/tss-lib/tss"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -43,9 +44,23 @@ type TssCommon struct {
 	blameMgr            *blame.Manager
 	finishedPeers       map[string]bool
 	culprits            []*btss.PartyID
+	unicastAcks         *unicastAckTracker
+	deadLetters         *DeadLetterSink
+	ejectLock           *sync.RWMutex
+	ejectedPeers        map[peer.ID]bool
+	faultInjector       FaultInjector
+	shareRequests       *shareRequestTracker
+	peerRoundsLock      *sync.RWMutex
+	peerRounds          map[string]string
 }
 
 func NewTssCommon(peerID string, broadcastChannel chan *messages.BroadcastMsgChan, conf TssConfig, msgID string, privKey tcrypto.PrivKey) *TssCommon {
+	queueSize := conf.MaxQueuedMessages
+	if queueSize <= 0 {
+		queueSize = DefaultMaxQueuedMessages
+	}
+	blameMgr := blame.NewBlameManager()
+	blameMgr.SetDisabled(conf.DisableBlame)
 	return &TssCommon{
 		conf:                conf,
 		logger:              log.With().Str("module", "tsscommon").Logger(),
@@ -55,15 +70,21 @@ func NewTssCommon(peerID string, broadcastChannel chan *messages.BroadcastMsgCha
 		unConfirmedMsgLock:  &sync.Mutex{},
 		unConfirmedMessages: make(map[string]*LocalCacheItem),
 		broadcastChannel:    broadcastChannel,
-		TssMsg:              make(chan *p2p.Message),
+		TssMsg:              make(chan *p2p.Message, queueSize),
 		P2PPeers:            nil,
 		msgID:               msgID,
 		localPeerID:         peerID,
 		privateKey:          privKey,
 		taskDone:            make(chan struct{}),
-		blameMgr:            blame.NewBlameManager(),
+		blameMgr:            blameMgr,
 		finishedPeers:       make(map[string]bool),
 		culprits:            []*btss.PartyID{},
+		unicastAcks:         newUnicastAckTracker(),
+		ejectLock:           &sync.RWMutex{},
+		ejectedPeers:        make(map[peer.ID]bool),
+		shareRequests:       newShareRequestTracker(),
+		peerRoundsLock:      &sync.RWMutex{},
+		peerRounds:          make(map[string]string),
 	}
 }
 
@@ -72,9 +93,58 @@ func (t *TssCommon) renderToP2P(broadcastMsg *messages.BroadcastMsgChan) {
 		t.logger.Warn().Msg("broadcast channel is not set")
 		return
 	}
+	if t.faultInjector != nil && !t.faultInjector.Outbound(broadcastMsg) {
+		return
+	}
 	t.broadcastChannel <- broadcastMsg
 }
 
+// SetDeadLetterSink set the sink that records the inbound messages we fail to process
+func (t *TssCommon) SetDeadLetterSink(sink *DeadLetterSink) {
+	t.deadLetters = sink
+}
+
+func (t *TssCommon) recordDeadLetter(sender string, msgType messages.THORChainTSSMessageType, reason error, payload []byte) {
+	typeName := ""
+	if msgType != messages.Unknown {
+		typeName = msgType.String()
+	}
+	if err := t.deadLetters.Record(t.msgID, sender, typeName, reason.Error(), payload); err != nil {
+		t.logger.Error().Err(err).Msg("fail to record the dead letter")
+	}
+}
+
+// EjectPeer drop the messages of the given peer from now on, so the peer gets blamed when the ceremony times out
+func (t *TssCommon) EjectPeer(peerID peer.ID) {
+	t.ejectLock.Lock()
+	defer t.ejectLock.Unlock()
+	t.ejectedPeers[peerID] = true
+}
+
+func (t *TssCommon) isEjected(peerID peer.ID) bool {
+	t.ejectLock.RLock()
+	defer t.ejectLock.RUnlock()
+	return t.ejectedPeers[peerID]
+}
+
+func (t *TssCommon) updatePeerRound(peerID peer.ID, round string) {
+	t.peerRoundsLock.Lock()
+	defer t.peerRoundsLock.Unlock()
+	t.peerRounds[peerID.String()] = round
+}
+
+// GetPeerRounds return the round of the last message we have received from each peer, the peers we have not heard
+// from are not in it
+func (t *TssCommon) GetPeerRounds() map[string]string {
+	t.peerRoundsLock.RLock()
+	defer t.peerRoundsLock.RUnlock()
+	result := make(map[string]string, len(t.peerRounds))
+	for k, v := range t.peerRounds {
+		result[k] = v
+	}
+	return result
+}
+
 // GetConf get current configuration for Tss
 func (t *TssCommon) GetConf() TssConfig {
 	return t.conf
@@ -140,7 +210,12 @@ func (t *TssCommon) processInvalidMsgBlame(wireMsg *messages.WireMessage, round
 			msgBody = invalidMsg.Message
 			sig = invalidMsg.Sig
 		}
-		blameNodes = append(blameNodes, blame.NewNode(pk, msgBody, sig))
+		blameNode := blame.NewNode(pk, msgBody, sig)
+		if t.conf.RetainTssErrors {
+			// we keep the signed message in BlameData as the proof, the error goes to its own field
+			blameNode.TssError = err.Error()
+		}
+		blameNodes = append(blameNodes, blameNode)
 	}
 	t.blameMgr.GetBlame().SetBlame(blame.TssBrokenMsg, blameNodes, unicast)
 	return fmt.Errorf("fail to set bytes to local party: %w", err)
@@ -243,6 +318,12 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 	if nil == wrappedMsg {
 		return errors.New("invalid wireMessage")
 	}
+	if t.faultInjector != nil && !t.faultInjector.Inbound(wrappedMsg, peerID) {
+		return nil
+	}
+	if senderID, err := peer.Decode(peerID); err == nil && t.isEjected(senderID) {
+		return fmt.Errorf("drop the message from ejected peer %s", peerID)
+	}
 
 	switch wrappedMsg.MessageType {
 	case messages.TSSKeyGenMsg, messages.TSSKeySignMsg:
@@ -280,6 +361,12 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 			}
 			return nil
 		}
+	case messages.TSSUnicastAck:
+		var ack messages.UnicastAck
+		if err := json.Unmarshal(wrappedMsg.Payload, &ack); nil != err {
+			return fmt.Errorf("fail to unmarshal unicast ack: %w", err)
+		}
+		return t.processUnicastAck(&ack, peerID)
 	case messages.TSSControlMsg:
 		var wireMsg messages.TssControl
 		if err := json.Unmarshal(wrappedMsg.Payload, &wireMsg); nil != err {
@@ -291,8 +378,18 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 				t.logger.Error().Err(err).Msg("error in decode the peer")
 				return err
 			}
+			if signed, err := t.validateShareRequest(&wireMsg, decodedPeerID); err != nil {
+				if signed {
+					t.blameShareRequest(&wireMsg, decodedPeerID)
+				}
+				return fmt.Errorf("reject the share request from peer %s: %w", peerID, err)
+			}
 			return t.processRequestMsgFromPeer([]peer.ID{decodedPeerID}, &wireMsg, false)
 		}
+		// we check the share before we consume the request, so that a bogus response does not cancel it
+		if err := validateShareResponse(&wireMsg); err != nil {
+			return fmt.Errorf("reject the share response from peer %s: %w", peerID, err)
+		}
 		exist := t.blameMgr.GetShareMgr().QueryAndDelete(wireMsg.ReqHash)
 		if !exist {
 			t.logger.Debug().Msg("this request does not exit, maybe already processed")
@@ -300,6 +397,8 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 		}
 		t.logger.Debug().Msg("we got the missing share from the peer")
 		return t.processTSSMsg(wireMsg.Msg, wireMsg.RequestType, true)
+	default:
+		return fmt.Errorf("unknown message type: %s", wrappedMsg.MessageType)
 	}
 
 	return nil
@@ -393,6 +492,12 @@ func (t *TssCommon) ProcessOutCh(msg btss.Message, msgType messages.THORChainTSS
 			peerIDs = append(peerIDs, peerID)
 		}
 	}
+	// we keep the unicast message until the receiver acknowledges it, so we can resend it if it gets lost
+	if !r.IsBroadcast {
+		for _, el := range peerIDs {
+			t.unicastAcks.add(wireMsg.GetCacheKey(), el, wrappedMsg)
+		}
+	}
 	t.renderToP2P(&messages.BroadcastMsgChan{
 		WrappedMessage: wrappedMsg,
 		PeersID:        peerIDs,
@@ -434,6 +539,7 @@ func (t *TssCommon) applyShare(localCacheItem *LocalCacheItem, threshold int, ke
 	t.logger.Debug().Msgf("remove key: %s", key)
 	// the information had been confirmed by all party , we don't need it anymore
 	t.removeKey(key)
+	t.shareRequests.done(key)
 	return nil
 }
 
@@ -465,17 +571,19 @@ func (t *TssCommon) requestShareFromPeer(localCacheItem *LocalCacheItem, thresho
 	switch msgType {
 	case messages.TSSKeyGenVerMsg:
 		msg.RequestType = messages.TSSKeyGenMsg
-		return t.processRequestMsgFromPeer(peersIDs, msg, true)
 	case messages.TSSKeySignVerMsg:
 		msg.RequestType = messages.TSSKeySignMsg
-		return t.processRequestMsgFromPeer(peersIDs, msg, true)
 	case messages.TSSKeySignMsg, messages.TSSKeyGenMsg:
 		msg.RequestType = msgType
-		return t.processRequestMsgFromPeer(peersIDs, msg, true)
 	default:
 		t.logger.Debug().Msg("unknown message type")
 		return nil
 	}
+	if t.conf.ShareRequestRetries > 0 {
+		// the peers may never answer, so we keep asking until we get the share or run out of retries
+		t.shareRequests.add(key, msg, peersIDs)
+	}
+	return t.processRequestMsgFromPeer(peersIDs, msg, true)
 }
 
 func (t *TssCommon) processVerMsg(broadcastConfirmMsg *messages.BroadcastConfirmMessage, msgType messages.THORChainTSSMessageType) error {
@@ -583,6 +691,9 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		t.logger.Error().Msg("error in find the data owner")
 		return errors.New("error in find the data owner")
 	}
+	if ownerPeerID, ok := t.PartyIDtoP2PID[dataOwner.Id]; ok && t.isEjected(ownerPeerID) {
+		return fmt.Errorf("drop the message of ejected peer %s", ownerPeerID)
+	}
 	keyBytes := dataOwner.GetKey()
 	var pk secp256k1.PubKeySecp256k1
 	copy(pk[:], keyBytes)
@@ -591,10 +702,20 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		t.logger.Error().Msg("fail to verify the signature")
 		return errors.New("signature verify failed")
 	}
+	if ownerPeerID, ok := t.PartyIDtoP2PID[dataOwner.Id]; ok {
+		t.updatePeerRound(ownerPeerID, wireMsg.RoundInfo)
+	}
 
 	// for the unicast message, we only update it local party
 	if !wireMsg.Routing.IsBroadcast {
 		t.logger.Debug().Msgf("msg from %s to %+v", wireMsg.Routing.From, wireMsg.Routing.To)
+		if !forward {
+			if dataOwnerPeerID, ok := t.PartyIDtoP2PID[wireMsg.Routing.From.Id]; ok {
+				if err := t.sendUnicastAck(wireMsg, dataOwnerPeerID); err != nil {
+					t.logger.Error().Err(err).Msg("fail to send the unicast ack")
+				}
+			}
+		}
 		return t.updateLocal(wireMsg)
 	}
 
@@ -682,10 +803,22 @@ func (t *TssCommon) ProcessInboundMessages(finishChan chan struct{}, wg *sync.Wa
 	t.logger.Debug().Msg("start processing inbound messages")
 	defer wg.Done()
 	defer t.logger.Debug().Msg("stop processing inbound messages")
+	resendTicker := time.NewTicker(unicastAckTimeout / 2)
+	defer resendTicker.Stop()
+	var shareRequestTick <-chan time.Time
+	if t.conf.ShareRequestRetries > 0 {
+		shareRequestTicker := time.NewTicker(t.shareRequestInterval() / 2)
+		defer shareRequestTicker.Stop()
+		shareRequestTick = shareRequestTicker.C
+	}
 	for {
 		select {
 		case <-finishChan:
 			return
+		case <-resendTicker.C:
+			t.resendUnackedUnicast()
+		case <-shareRequestTick:
+			t.retryShareRequests()
 		case m, ok := <-t.TssMsg:
 			if !ok {
 				return
@@ -693,12 +826,14 @@ func (t *TssCommon) ProcessInboundMessages(finishChan chan struct{}, wg *sync.Wa
 			var wrappedMsg messages.WrappedMessage
 			if err := json.Unmarshal(m.Payload, &wrappedMsg); nil != err {
 				t.logger.Error().Err(err).Msg("fail to unmarshal wrapped message bytes")
+				t.recordDeadLetter(m.PeerID.String(), messages.Unknown, err, m.Payload)
 				continue
 			}
 
 			err := t.ProcessOneMessage(&wrappedMsg, m.PeerID.String())
 			if err != nil {
 				t.logger.Error().Err(err).Msg("fail to process the received message")
+				t.recordDeadLetter(m.PeerID.String(), wrappedMsg.MessageType, err, wrappedMsg.Payload)
 			}
 
 		}
@@ -281,6 +281,8 @@ func (t *TssCommon) processRequestMsgFromPeer(peersID []peer.ID, msg *messages.T
 			return nil
 		}
 		msg.Msg = storedMsg
+	} else if err := t.signControlMsg(msg); err != nil {
+		return err
 	}
 
 	data, err := json.Marshal(msg)
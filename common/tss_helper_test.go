@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"path"
+	"time"
 
 	btss "github.com/binance-chain/tss-lib/tss"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -177,3 +178,44 @@ func (t *tssHelpSuite) TestGetMsgRound(c *C) {
 	c.Assert(ret, Equals, blame.RoundInfo{Index: 1, RoundMsg: messages.KEYGEN2aUnicast})
 	c.Assert(err, IsNil)
 }
+
+func (t *tssHelpSuite) TestProtocolTimeout(c *C) {
+	conf := TssConfig{
+		ProtocolTimeout: time.Minute,
+		KeySignTimeout:  time.Second,
+	}
+	c.Assert(conf.KeyGenProtocolTimeout(), Equals, time.Minute)
+	c.Assert(conf.KeySignProtocolTimeout(), Equals, time.Second)
+}
+
+func (t *tssHelpSuite) TestCeremonyDeadline(c *C) {
+	c.Assert(TssConfig{}.CeremonyDeadline().IsZero(), Equals, true)
+	deadline, stop := DeadlineTimer(TssConfig{}.CeremonyDeadline())
+	c.Assert(deadline, IsNil)
+	stop()
+
+	deadline, stop = DeadlineTimer(TssConfig{MaxCeremonyDuration: 100 * time.Millisecond}.CeremonyDeadline())
+	defer stop()
+	select {
+	case <-deadline:
+	case <-time.After(time.Second):
+		c.Fatal("the deadline does not fire")
+	}
+}
+
+func (t *tssHelpSuite) TestTssCommon_EjectPeer(c *C) {
+	tssCommon := NewTssCommon("", nil, TssConfig{}, "msgID", nil)
+	pid, err := peer.Decode("16Uiu2HAmACG5DtqmQsHtXg4G2sLS65ttv84e7MrL4kapkjfmhxAp")
+	c.Assert(err, IsNil)
+	taskDone, err := json.Marshal(messages.TssTaskNotifier{TaskDone: false})
+	c.Assert(err, IsNil)
+	wrappedMsg := &messages.WrappedMessage{
+		MessageType: messages.TSSTaskDone,
+		MsgID:       "msgID",
+		Payload:     taskDone,
+	}
+	c.Assert(tssCommon.ProcessOneMessage(wrappedMsg, pid.String()), IsNil)
+	tssCommon.EjectPeer(pid)
+	c.Assert(tssCommon.isEjected(pid), Equals, true)
+	c.Assert(tssCommon.ProcessOneMessage(wrappedMsg, pid.String()), NotNil)
+}
@@ -268,8 +268,9 @@ func (t *TssTestSuite) testProcessControlMsg(c *C, tssCommonStruct *TssCommon) {
 
 	err = tssCommonStruct.ProcessOneMessage(&wrappedMsg, "1")
 	c.Assert(err, NotNil)
+	// the peer is not in the committee, and the request key is invalid
 	err = tssCommonStruct.ProcessOneMessage(&wrappedMsg, "16Uiu2HAmACG5DtqmQsHtXg4G2sLS65ttv84e7MrL4kapkjfmhxAp")
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	tssCommonStruct.blameMgr.GetShareMgr().Set("testHash")
 
 	msg := messages.WireMessage{
@@ -292,7 +293,7 @@ func (t *TssTestSuite) testProcessControlMsg(c *C, tssCommonStruct *TssCommon) {
 	}
 
 	err = tssCommonStruct.ProcessOneMessage(&wrappedMsg, "16Uiu2HAmACG5DtqmQsHtXg4G2sLS65ttv84e7MrL4kapkjfmhxAp")
-	c.Assert(err, ErrorMatches, "invalid wireMsg")
+	c.Assert(err, ErrorMatches, ".*invalid share in the response")
 }
 
 func (t *TssTestSuite) testProcessTaskDone(c *C, tssCommonStruct *TssCommon) {
@@ -397,6 +398,8 @@ func (t *TssTestSuite) TestTssCommon(c *C) {
 	sk := secp256k1.GenPrivKey()
 	tssCommon := NewTssCommon(peerID.String(), broadcastChannel, TssConfig{}, "message-id", sk)
 	c.Assert(tssCommon, NotNil)
+	c.Assert(cap(tssCommon.TssMsg), Equals, DefaultMaxQueuedMessages)
+	c.Assert(cap(NewTssCommon(peerID.String(), broadcastChannel, TssConfig{MaxQueuedMessages: 16}, "message-id", sk).TssMsg), Equals, 16)
 	stopchan := make(chan struct{})
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -449,4 +452,43 @@ func (t *TssTestSuite) TestProcessInvalidMsgBlame(c *C) {
 	}
 	// for the last one, since we do not store the msg before hand, it should return no record of this party
 	c.Assert(blameResult.BlameNodes[2].BlameData, HasLen, 0)
+	for _, el := range blameResult.BlameNodes {
+		c.Assert(el.Category, Equals, blame.CategoryMalicious)
+	}
+	c.Assert(blameResult.IsMalicious(), Equals, true)
+	c.Assert(blameResult.BlameNodes[0].TssError, Equals, "")
+}
+
+func (t *TssTestSuite) TestProcessInvalidMsgBlameWithTssError(c *C) {
+	tssCommonStruct, peerPartiesID, partiesID := setupProcessVerMsgEnv(c, t.privKey, testBlamePubKeys, 4)
+	tssCommonStruct.conf.RetainTssErrors = true
+	sender := findSender(partiesID)
+	roundInfo := "round testMessage"
+	wrappedMsg := fabricateTssMsg(c, t.privKey, sender, roundInfo, "testTssError", "123", messages.TSSKeyGenMsg)
+	var wiredMsg messages.WireMessage
+	c.Assert(json.Unmarshal(wrappedMsg.Payload, &wiredMsg), IsNil)
+
+	fakeErr := btss.NewError(errors.New("test error"), "test task", 1, nil, peerPartiesID[0])
+	c.Assert(tssCommonStruct.processInvalidMsgBlame(&wiredMsg, blame.RoundInfo{RoundMsg: roundInfo}, fakeErr), NotNil)
+	blameResult := tssCommonStruct.GetBlameMgr().GetBlame()
+	c.Assert(blameResult.BlameNodes, HasLen, 1)
+	c.Assert(blameResult.BlameNodes[0].TssError, Equals, fakeErr.Error())
+}
+
+func (t *TssTestSuite) TestPeerRounds(c *C) {
+	tssCommonStruct, _, partiesID := setupProcessVerMsgEnv(c, t.privKey, testBlamePubKeys, 4)
+	c.Assert(tssCommonStruct.GetPeerRounds(), HasLen, 0)
+	sender := findSender(partiesID)
+	senderPeer, err := conversion.GetPeerIDFromPartyID(sender)
+	c.Assert(err, IsNil)
+	for _, round := range []string{"round1", "round2"} {
+		wrappedMsg := fabricateTssMsg(c, t.privKey, sender, round, "testPeerRounds-"+round, "123", messages.TSSKeyGenMsg)
+		c.Assert(tssCommonStruct.ProcessOneMessage(wrappedMsg, senderPeer.String()), IsNil)
+	}
+	rounds := tssCommonStruct.GetPeerRounds()
+	c.Assert(rounds, HasLen, 1)
+	c.Assert(rounds[senderPeer.String()], Equals, "round2")
+	// the caller gets a copy
+	rounds[senderPeer.String()] = "round3"
+	c.Assert(tssCommonStruct.GetPeerRounds()[senderPeer.String()], Equals, "round2")
 }
@@ -4,15 +4,93 @@ import (
 	"time"
 )
 
+// DefaultMaxQueuedMessages is the capacity of the inbound message queue of a ceremony if it is not configured
+const DefaultMaxQueuedMessages = 1024
+
 type TssConfig struct {
-	// Party Timeout defines how long do we wait for the party to form
-	PartyTimeout time.Duration
+	// JoinPartyTimeout defines how long do we wait for the peers to come online and form the party
+	JoinPartyTimeout time.Duration
+	// ProtocolTimeout defines how long do we wait for the TSS rounds once the party is formed, it applies to both
+	// keygen and keysign unless KeyGenTimeout/KeySignTimeout is set
+	ProtocolTimeout time.Duration
 	// KeyGenTimeoutSeconds defines how long do we wait the keygen parties to pass messages along
 	KeyGenTimeout time.Duration
 	// KeySignTimeoutSeconds defines how long do we wait keysign
 	KeySignTimeout time.Duration
 	// Pre-parameter define the pre-parameter generations timeout
 	PreParamTimeout time.Duration
+	// RequireSignedControlMsg defines whether we reject the share requests that are not signed by the committee
+	// member who sends them
+	RequireSignedControlMsg bool
+	// RetainTssErrors defines whether we attach the raw tss-lib error to the nodes it blames
+	RetainTssErrors bool
+	// JoinPartyConcurrency defines how many join party requests we send at the same time
+	JoinPartyConcurrency int
+	// UnixSocketPath defines the unix socket we serve the API on, so only the local processes can reach it
+	UnixSocketPath string
+	// MaxCeremonyDuration defines the hard deadline of the whole keygen/keysign from the join party to the last
+	// round, regardless of the per stage timeouts, the ceremony is aborted with the timeout blame once it is
+	// reached, 0 means no deadline
+	MaxCeremonyDuration time.Duration
+	// ShareRequestRetries defines how many more times do we request the share from the peers, when all the parties
+	// confirmed its hash but we never received the share itself, 0 means we only request it once
+	ShareRequestRetries int
+	// ShareRequestInterval defines how long do we wait for the requested share before we request it again
+	ShareRequestInterval time.Duration
+	// TssMaxPayload defines the max payload of the keygen/keysign messages we accept, 0 means p2p.MaxPayload
+	TssMaxPayload uint
+	// JoinPartyMaxPayload defines the max payload of the join party requests we accept, 0 means the default
+	JoinPartyMaxPayload uint
+	// DisableBlame turns the blame attribution off for the trusted deployments, the failed ceremonies still return
+	// the error but the blame is always empty
+	DisableBlame bool
+	// DeadLetterFile defines the file we record the inbound messages we fail to process to, empty means disabled
+	DeadLetterFile string
+	// MaxQueuedMessages defines how many inbound messages of a ceremony we queue while the local party is not
+	// ready to process them, once the queue is full the stream waits for room before it gives up on the message
+	MaxQueuedMessages int
+	// AnnounceAllowCIDRs defines the networks of the addresses we announce to the peers, empty means all of them
+	AnnounceAllowCIDRs []string
+	// AnnounceDenyCIDRs defines the networks of the addresses we never announce to the peers
+	AnnounceDenyCIDRs []string
+	// SignatureQuorum defines how many distinct signers should send us the same valid signature, before a node that
+	// is not in the keysign party accepts it, default to 1
+	SignatureQuorum int
+}
+
+// KeyGenProtocolTimeout return the timeout of the keygen rounds
+func (c TssConfig) KeyGenProtocolTimeout() time.Duration {
+	if c.KeyGenTimeout > 0 {
+		return c.KeyGenTimeout
+	}
+	return c.ProtocolTimeout
+}
+
+// CeremonyDeadline return the time by which a ceremony that starts now has to finish, it is the zero time if no
+// max duration is set
+func (c TssConfig) CeremonyDeadline() time.Time {
+	if c.MaxCeremonyDuration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.MaxCeremonyDuration)
+}
+
+// DeadlineTimer return the channel that fires at the given deadline, the channel never fires for the zero time.
+// The returned function releases the timer
+func DeadlineTimer(deadline time.Time) (<-chan time.Time, func()) {
+	if deadline.IsZero() {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(time.Until(deadline))
+	return timer.C, func() { timer.Stop() }
+}
+
+// KeySignProtocolTimeout return the timeout of the keysign rounds
+func (c TssConfig) KeySignProtocolTimeout() time.Duration {
+	if c.KeySignTimeout > 0 {
+		return c.KeySignTimeout
+	}
+	return c.ProtocolTimeout
 }
 
 type TssStatus struct {
@@ -28,4 +106,50 @@ type TssStatus struct {
 	// FailedKeySign indicates how many times we run keysign unsuccessfully(the invalid http request is not counted as
 	// the failure of keysign)
 	FailedKeySign uint64 `json:"failed_keysign"`
+	// DroppedMessages indicates how many inbound messages we dropped as the queue of the ceremony stayed full
+	DroppedMessages uint64 `json:"dropped_messages"`
+}
+
+// CeremonyStats is the statistic of one type of ceremony
+type CeremonyStats struct {
+	Successful  uint64  `json:"successful"`
+	Failed      uint64  `json:"failed"`
+	SuccessRate float64 `json:"success_rate"`
+	// AvgDuration is the average duration of the finished ceremonies in seconds
+	AvgDuration float64 `json:"average_duration"`
+}
+
+// ActiveCeremony is a ceremony the node is running at the moment
+type ActiveCeremony struct {
+	MsgID     string    `json:"message_id"`
+	Type      string    `json:"type"`
+	StartedAt time.Time `json:"started_at"`
+	// PeerRounds is the round of the last message we have received from each peer, so the lagging peers stand out
+	PeerRounds map[string]string `json:"peer_rounds,omitempty"`
+}
+
+// KeyInfo is the public information of a key share this node holds
+type KeyInfo struct {
+	PubKey          string   `json:"pub_key"`
+	ParticipantKeys []string `json:"participant_keys"`
+}
+
+// KeyListing is the key shares this node holds sorted by pub key, nodes holding the same set of keys have the same hash
+type KeyListing struct {
+	Keys []KeyInfo `json:"keys"`
+	Hash string    `json:"hash"`
+}
+
+// TssStats is the aggregated statistic of the node for the dashboards
+type TssStats struct {
+	// Uptime is how long the node has been running in seconds
+	Uptime           float64           `json:"uptime"`
+	Keygen           CeremonyStats     `json:"keygen"`
+	Keysign          CeremonyStats     `json:"keysign"`
+	ActiveCeremonies []ActiveCeremony  `json:"active_ceremonies"`
+	ConnectedPeers   int               `json:"connected_peers"`
+	BlameCounts      map[string]uint64 `json:"blame_counts"`
+	DroppedMessages  uint64            `json:"dropped_messages"`
+	// JoinPartyInFlight is how many join party requests are being sent at the moment
+	JoinPartyInFlight int64 `json:"join_party_in_flight"`
 }
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+const (
+	// unicastAckTimeout defines how long do we wait for the ack before we resend the unicast message
+	unicastAckTimeout = 2 * time.Second
+	// maxUnicastResend defines how many times we resend a unicast message that is not acknowledged
+	maxUnicastResend = 3
+)
+
+type pendingUnicast struct {
+	wrappedMsg messages.WrappedMessage
+	peerID     peer.ID
+	sentAt     time.Time
+	resent     int
+}
+
+// unicastAckTracker keeps the unicast messages we sent until the receiver acknowledges them
+type unicastAckTracker struct {
+	lock    *sync.Mutex
+	pending map[string]*pendingUnicast
+}
+
+func newUnicastAckTracker() *unicastAckTracker {
+	return &unicastAckTracker{
+		lock:    &sync.Mutex{},
+		pending: make(map[string]*pendingUnicast),
+	}
+}
+
+func unicastAckKey(msgKey string, peerID peer.ID) string {
+	return fmt.Sprintf("%s-%s", msgKey, peerID.String())
+}
+
+func (ut *unicastAckTracker) add(msgKey string, peerID peer.ID, wrappedMsg messages.WrappedMessage) {
+	ut.lock.Lock()
+	defer ut.lock.Unlock()
+	ut.pending[unicastAckKey(msgKey, peerID)] = &pendingUnicast{
+		wrappedMsg: wrappedMsg,
+		peerID:     peerID,
+		sentAt:     time.Now(),
+	}
+}
+
+// ack remove the pending message, it returns false if we are not waiting for this ack
+func (ut *unicastAckTracker) ack(msgKey string, peerID peer.ID) bool {
+	ut.lock.Lock()
+	defer ut.lock.Unlock()
+	key := unicastAckKey(msgKey, peerID)
+	if _, ok := ut.pending[key]; !ok {
+		return false
+	}
+	delete(ut.pending, key)
+	return true
+}
+
+// expired return the messages that are not acknowledged in time, the ones that run out of resend are dropped
+func (ut *unicastAckTracker) expired(now time.Time) []*pendingUnicast {
+	ut.lock.Lock()
+	defer ut.lock.Unlock()
+	var result []*pendingUnicast
+	for key, item := range ut.pending {
+		if now.Sub(item.sentAt) < unicastAckTimeout {
+			continue
+		}
+		if item.resent >= maxUnicastResend {
+			delete(ut.pending, key)
+			continue
+		}
+		item.resent++
+		item.sentAt = now
+		result = append(result, item)
+	}
+	return result
+}
+
+func (ut *unicastAckTracker) size() int {
+	ut.lock.Lock()
+	defer ut.lock.Unlock()
+	return len(ut.pending)
+}
+
+// sendUnicastAck tell the sender of the unicast message that we have received it
+func (t *TssCommon) sendUnicastAck(wireMsg *messages.WireMessage, peerID peer.ID) error {
+	ack := messages.UnicastAck{
+		Key: wireMsg.GetCacheKey(),
+	}
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the unicast ack: %w", err)
+	}
+	t.renderToP2P(&messages.BroadcastMsgChan{
+		WrappedMessage: messages.WrappedMessage{
+			MessageType: messages.TSSUnicastAck,
+			MsgID:       t.msgID,
+			Payload:     data,
+		},
+		PeersID: []peer.ID{peerID},
+	})
+	return nil
+}
+
+func (t *TssCommon) processUnicastAck(ack *messages.UnicastAck, peerID string) error {
+	pID, err := peer.Decode(peerID)
+	if err != nil {
+		return fmt.Errorf("fail to decode the peer id(%s): %w", peerID, err)
+	}
+	if !t.unicastAcks.ack(ack.Key, pID) {
+		t.logger.Debug().Msgf("unexpected unicast ack of %s from peer %s", ack.Key, peerID)
+	}
+	return nil
+}
+
+// resendUnackedUnicast resend the unicast messages whose receivers have not acknowledged them in time
+func (t *TssCommon) resendUnackedUnicast() {
+	for _, item := range t.unicastAcks.expired(time.Now()) {
+		t.logger.Warn().Msgf("no ack from peer %s, resend the unicast message(%d/%d)", item.peerID, item.resent, maxUnicastResend)
+		t.renderToP2P(&messages.BroadcastMsgChan{
+			WrappedMessage: item.wrappedMsg,
+			PeersID:        []peer.ID{item.peerID},
+		})
+	}
+}
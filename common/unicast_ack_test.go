@@ -0,0 +1,41 @@
+package common
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+)
+
+type UnicastAckTestSuite struct{}
+
+var _ = Suite(&UnicastAckTestSuite{})
+
+func (UnicastAckTestSuite) TestUnicastAckTracker(c *C) {
+	tracker := newUnicastAckTracker()
+	peer1 := conversion.GetRandomPeerID()
+	peer2 := conversion.GetRandomPeerID()
+	msg := messages.WrappedMessage{MessageType: messages.TSSKeyGenMsg, MsgID: "msg1"}
+	tracker.add("1-round2", peer1, msg)
+	tracker.add("1-round2", peer2, msg)
+	c.Assert(tracker.size(), Equals, 2)
+	c.Assert(tracker.ack("1-round2", peer1), Equals, true)
+	c.Assert(tracker.ack("1-round2", peer1), Equals, false)
+	c.Assert(tracker.size(), Equals, 1)
+
+	// not expired yet
+	c.Assert(tracker.expired(time.Now()), HasLen, 0)
+	now := time.Now()
+	for i := 1; i <= maxUnicastResend; i++ {
+		now = now.Add(unicastAckTimeout)
+		items := tracker.expired(now)
+		c.Assert(items, HasLen, 1)
+		c.Assert(items[0].peerID, Equals, peer2)
+		c.Assert(items[0].resent, Equals, i)
+	}
+	// we give up after the max resend
+	c.Assert(tracker.expired(now.Add(unicastAckTimeout)), HasLen, 0)
+	c.Assert(tracker.size(), Equals, 0)
+}
@@ -105,10 +105,21 @@ func SetupIDMaps(parties map[string]*btss.PartyID, partyIDtoP2PID map[string]pee
 	return nil
 }
 
+// checkPartyKey make sure no two parties share the same key, the distinct pub key strings may decode to the same
+// bytes, and tss-lib behaves unpredictably with the duplicated party keys
+func checkPartyKey(usedKeys map[string]string, key *big.Int, pubKey string) error {
+	if owner, ok := usedKeys[key.String()]; ok {
+		return fmt.Errorf("duplicated party key, pub key(%s) and pub key(%s) decode to the same key", owner, pubKey)
+	}
+	usedKeys[key.String()] = pubKey
+	return nil
+}
+
 func GetParties(keys []string, localPartyKey string) ([]*btss.PartyID, *btss.PartyID, error) {
 	var localPartyID *btss.PartyID
 	var unSortedPartiesID []*btss.PartyID
 	sort.Strings(keys)
+	usedKeys := make(map[string]string, len(keys))
 	for idx, item := range keys {
 		pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, item)
 		if err != nil {
@@ -116,6 +127,9 @@ func GetParties(keys []string, localPartyKey string) ([]*btss.PartyID, *btss.Par
 		}
 		secpPk := pk.(secp256k1.PubKeySecp256k1)
 		key := new(big.Int).SetBytes(secpPk[:])
+		if err := checkPartyKey(usedKeys, key, item); err != nil {
+			return nil, nil, err
+		}
 		// Set up the parameters
 		// Note: The `id` and `moniker` fields are for convenience to allow you to easily track participants.
 		// The `id` should be a unique string representing this party in the network and `moniker` can be anything (even left blank).
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math/big"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/binance-chain/tss-lib/crypto"
@@ -86,6 +87,25 @@ func (p *ConversionTestSuite) TestGetParties(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (p *ConversionTestSuite) TestGetPartiesDuplicatedKey(c *C) {
+	keys := append([]string{}, p.testPubKeys...)
+	keys = append(keys, p.testPubKeys[1])
+	_, _, err := GetParties(keys, p.testPubKeys[0])
+	c.Assert(err, ErrorMatches, "duplicated party key.*")
+
+	// the all-uppercase bech32 string is a distinct string that decodes to the same key
+	upper := strings.ToUpper(p.testPubKeys[1])
+	pk1, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, p.testPubKeys[1])
+	c.Assert(err, IsNil)
+	pk2, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, upper)
+	c.Assert(err, IsNil)
+	c.Assert(pk2.Equals(pk1), Equals, true)
+	keys = append([]string{}, p.testPubKeys...)
+	keys = append(keys, upper)
+	_, _, err = GetParties(keys, p.testPubKeys[0])
+	c.Assert(err, ErrorMatches, "duplicated party key.*")
+}
+
 //
 func (p *ConversionTestSuite) TestGetPeerIDFromPartyID(c *C) {
 	_, localParty, err := GetParties(p.testPubKeys, p.testPubKeys[0])
@@ -0,0 +1,103 @@
+package conversion
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// MaxDerivationIndex is the largest non-hardened derivation index, the hardened derivation needs the private key
+// which no party of the committee holds
+const MaxDerivationIndex = 1<<31 - 1
+
+// getChildTweak compute the BIP32 non-hardened tweak of the given index, as the pool key has no chain code, we use
+// the sha256 hash of the compressed pool pub key instead
+func getChildTweak(pubKey *btcec.PublicKey, index uint32) (*big.Int, error) {
+	if index > MaxDerivationIndex {
+		return nil, fmt.Errorf("invalid derivation index(%d), hardened derivation is not supported", index)
+	}
+	compressed := pubKey.SerializeCompressed()
+	chainCode := sha256.Sum256(compressed)
+	mac := hmac.New(sha512.New, chainCode[:])
+	data := make([]byte, len(compressed)+4)
+	copy(data, compressed)
+	binary.BigEndian.PutUint32(data[len(compressed):], index)
+	if _, err := mac.Write(data); err != nil {
+		return nil, fmt.Errorf("fail to compute the child tweak: %w", err)
+	}
+	tweak := new(big.Int).SetBytes(mac.Sum(nil)[:32])
+	if tweak.Sign() == 0 || tweak.Cmp(btcec.S256().N) >= 0 {
+		return nil, fmt.Errorf("invalid child tweak of index(%d), please use the next index", index)
+	}
+	return tweak, nil
+}
+
+// addTweak return the point + tweak*G
+func addTweak(x, y, tweak *big.Int) (*crypto.ECPoint, error) {
+	curve := btcec.S256()
+	tx, ty := curve.ScalarBaseMult(tweak.Bytes())
+	cx, cy := curve.Add(x, y, tx, ty)
+	return crypto.NewECPoint(curve, cx, cy)
+}
+
+// DeriveChildPubKey derive the child pub key and its address of the given index from the pool pub key
+func DeriveChildPubKey(poolPubKey string, index uint32) (string, types.AccAddress, error) {
+	pubKey, err := ParseSecp256PubKey(poolPubKey)
+	if err != nil {
+		return "", types.AccAddress{}, err
+	}
+	tweak, err := getChildTweak(pubKey, index)
+	if err != nil {
+		return "", types.AccAddress{}, err
+	}
+	childPoint, err := addTweak(pubKey.X, pubKey.Y, tweak)
+	if err != nil {
+		return "", types.AccAddress{}, fmt.Errorf("fail to derive the child pub key: %w", err)
+	}
+	return GetTssPubKey(childPoint)
+}
+
+// DeriveLocalPartySaveData apply the additive tweak of the given index to the key share of the local party, once
+// every signer applies it, the committee signs with the child key instead of the pool key
+func DeriveLocalPartySaveData(data keygen.LocalPartySaveData, index uint32) (keygen.LocalPartySaveData, error) {
+	if data.ECDSAPub == nil || data.Xi == nil {
+		return keygen.LocalPartySaveData{}, errors.New("invalid local party save data")
+	}
+	poolPubKey := btcec.PublicKey{
+		Curve: btcec.S256(),
+		X:     data.ECDSAPub.X(),
+		Y:     data.ECDSAPub.Y(),
+	}
+	tweak, err := getChildTweak(&poolPubKey, index)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, err
+	}
+	// as the lagrange coefficients sum to one, adding the same tweak to every share adds it to the secret
+	derived := data
+	derived.Xi = new(big.Int).Add(data.Xi, tweak)
+	derived.Xi.Mod(derived.Xi, btcec.S256().N)
+	derived.BigXj = make([]*crypto.ECPoint, len(data.BigXj))
+	for i, el := range data.BigXj {
+		if el == nil {
+			return keygen.LocalPartySaveData{}, fmt.Errorf("invalid share point of party(%d)", i)
+		}
+		derived.BigXj[i], err = addTweak(el.X(), el.Y(), tweak)
+		if err != nil {
+			return keygen.LocalPartySaveData{}, fmt.Errorf("fail to derive the share point of party(%d): %w", i, err)
+		}
+	}
+	derived.ECDSAPub, err = addTweak(data.ECDSAPub.X(), data.ECDSAPub.Y(), tweak)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, fmt.Errorf("fail to derive the child pub key: %w", err)
+	}
+	return derived, nil
+}
@@ -0,0 +1,86 @@
+package conversion
+
+import (
+	"math/big"
+
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/btcsuite/btcd/btcec"
+	. "gopkg.in/check.v1"
+)
+
+type DerivationTestSuite struct{}
+
+var _ = Suite(&DerivationTestSuite{})
+
+func (*DerivationTestSuite) SetUpSuite(c *C) {
+	SetupBech32Prefix()
+}
+
+func (*DerivationTestSuite) TestDeriveChildPubKey(c *C) {
+	sk, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	point, err := crypto.NewECPoint(btcec.S256(), sk.X, sk.Y)
+	c.Assert(err, IsNil)
+	poolPubKey, _, err := GetTssPubKey(point)
+	c.Assert(err, IsNil)
+
+	child0, addr0, err := DeriveChildPubKey(poolPubKey, 0)
+	c.Assert(err, IsNil)
+	child1, addr1, err := DeriveChildPubKey(poolPubKey, 1)
+	c.Assert(err, IsNil)
+	c.Assert(child0, Not(Equals), poolPubKey)
+	c.Assert(child0, Not(Equals), child1)
+	c.Assert(addr0.String(), Not(Equals), addr1.String())
+	// the derivation is deterministic
+	again, _, err := DeriveChildPubKey(poolPubKey, 1)
+	c.Assert(err, IsNil)
+	c.Assert(again, Equals, child1)
+
+	// the child pub key matches the child private key
+	tweak, err := getChildTweak(sk.PubKey(), 1)
+	c.Assert(err, IsNil)
+	childKey := new(big.Int).Add(sk.D, tweak)
+	childKey.Mod(childKey, btcec.S256().N)
+	x, y := btcec.S256().ScalarBaseMult(childKey.Bytes())
+	childPoint, err := crypto.NewECPoint(btcec.S256(), x, y)
+	c.Assert(err, IsNil)
+	expected, _, err := GetTssPubKey(childPoint)
+	c.Assert(err, IsNil)
+	c.Assert(child1, Equals, expected)
+
+	_, _, err = DeriveChildPubKey(poolPubKey, MaxDerivationIndex+1)
+	c.Assert(err, NotNil)
+	_, _, err = DeriveChildPubKey("whatever", 1)
+	c.Assert(err, NotNil)
+}
+
+func (*DerivationTestSuite) TestDeriveLocalPartySaveData(c *C) {
+	sk, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	point, err := crypto.NewECPoint(btcec.S256(), sk.X, sk.Y)
+	c.Assert(err, IsNil)
+	poolPubKey, _, err := GetTssPubKey(point)
+	c.Assert(err, IsNil)
+	var data keygen.LocalPartySaveData
+	data.Xi = new(big.Int).Set(sk.D)
+	data.BigXj = []*crypto.ECPoint{point}
+	data.ECDSAPub = point
+
+	derived, err := DeriveLocalPartySaveData(data, 7)
+	c.Assert(err, IsNil)
+	// the original data is untouched
+	c.Assert(data.Xi.Cmp(sk.D), Equals, 0)
+	c.Assert(data.BigXj[0].Equals(point), Equals, true)
+	x, y := btcec.S256().ScalarBaseMult(derived.Xi.Bytes())
+	c.Assert(derived.BigXj[0].X().Cmp(x), Equals, 0)
+	c.Assert(derived.BigXj[0].Y().Cmp(y), Equals, 0)
+	childPubKey, _, err := GetTssPubKey(derived.ECDSAPub)
+	c.Assert(err, IsNil)
+	expected, _, err := DeriveChildPubKey(poolPubKey, 7)
+	c.Assert(err, IsNil)
+	c.Assert(childPubKey, Equals, expected)
+
+	_, err = DeriveLocalPartySaveData(keygen.LocalPartySaveData{}, 7)
+	c.Assert(err, NotNil)
+}
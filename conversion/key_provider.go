@@ -111,6 +111,28 @@ func GetPriKeyRawBytes(priKey tcrypto.PrivKey) ([]byte, error) {
 	return keyBytesArray[:], nil
 }
 
+// ParseSecp256PubKey parse the bech32 format account pub key to the btcec pub key
+func ParseSecp256PubKey(pk string) (*btcec.PublicKey, error) {
+	pubKey, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, pk)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse pub key(%s): %w", pk, err)
+	}
+	rawPk, ok := pubKey.(secp256k1.PubKeySecp256k1)
+	if !ok {
+		return nil, errors.New("pub key is not secp256k1.PubKeySecp256k1")
+	}
+	return btcec.ParsePubKey(rawPk[:], btcec.S256())
+}
+
+// EncryptToPubKey encrypt the data with ECIES, only the owner of the given bech32 format pub key can decrypt it
+func EncryptToPubKey(pk string, data []byte) ([]byte, error) {
+	pubKey, err := ParseSecp256PubKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return btcec.Encrypt(pubKey, data)
+}
+
 func CheckKeyOnCurve(pk string) (bool, error) {
 	pubKey, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, pk)
 	if err != nil {
@@ -4,7 +4,10 @@ import (
 	"encoding/base64"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 	. "gopkg.in/check.v1"
 )
 
@@ -51,6 +54,23 @@ func (*KeyProviderTestSuite) TestGetPriKey(c *C) {
 	c.Assert(result, HasLen, 32)
 }
 
+func (KeyProviderTestSuite) TestEncryptToPubKey(c *C) {
+	SetupBech32Prefix()
+	sk := secp256k1.GenPrivKey()
+	pk, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, sk.PubKey())
+	c.Assert(err, IsNil)
+	data := []byte("hello world")
+	encrypted, err := EncryptToPubKey(pk, data)
+	c.Assert(err, IsNil)
+	c.Assert(encrypted, Not(DeepEquals), data)
+	priKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), sk[:])
+	decrypted, err := btcec.Decrypt(priKey, encrypted)
+	c.Assert(err, IsNil)
+	c.Assert(decrypted, DeepEquals, data)
+	_, err = EncryptToPubKey("whatever", data)
+	c.Assert(err, NotNil)
+}
+
 func (KeyProviderTestSuite) TestGetPeerIDs(c *C) {
 	pubKeys := []string{
 		"thorpub1addwnpepqtctt9l4fddeh0krvdpxmqsxa5z9xsa0ac6frqfhm9fq6c6u5lck5s8fm4n",
@@ -0,0 +1,182 @@
+package conversion
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	tcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	keystoreCipher     = "aes-256-ctr"
+	keystoreKDF        = "pbkdf2"
+	keystorePRF        = "hmac-sha256"
+	keystoreKeyLen     = 32
+	keystoreIterations = 262144
+)
+
+// ErrKeystorePassphrase indicates the keystore can not be decrypted with the given passphrase
+var ErrKeystorePassphrase = errors.New("fail to decrypt the keystore with the given passphrase")
+
+type keystoreKDFParams struct {
+	PRF   string `json:"prf"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+	C     int    `json:"c"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+type keystoreCrypto struct {
+	Cipher       string               `json:"cipher"`
+	CipherText   string               `json:"ciphertext"`
+	CipherParams keystoreCipherParams `json:"cipherparams"`
+	KDF          string               `json:"kdf"`
+	KDFParams    keystoreKDFParams    `json:"kdfparams"`
+	MAC          string               `json:"mac"`
+}
+
+// keystore is the encrypted key file, it has the same format as the one exported by the tss-recovery tool
+type keystore struct {
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create the cipher: %w", err)
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
+
+func keystoreMAC(derivedKey, cipherText []byte) ([]byte, error) {
+	hasher := sha3.NewLegacyKeccak512()
+	if _, err := hasher.Write(derivedKey[16:32]); err != nil {
+		return nil, fmt.Errorf("fail to compute the mac: %w", err)
+	}
+	if _, err := hasher.Write(cipherText); err != nil {
+		return nil, fmt.Errorf("fail to compute the mac: %w", err)
+	}
+	return hasher.Sum(nil), nil
+}
+
+// EncryptKeystore encrypt the secp256k1 private key with the given passphrase into a keystore
+func EncryptKeystore(priKey tcrypto.PrivKey, passphrase string) ([]byte, error) {
+	rawBytes, err := GetPriKeyRawBytes(priKey)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("fail to generate the salt: %w", err)
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("fail to generate the iv: %w", err)
+	}
+	derivedKey := pbkdf2.Key([]byte(passphrase), salt, keystoreIterations, keystoreKeyLen, sha256.New)
+	cipherText, err := aesCTRXOR(derivedKey, rawBytes, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := keystoreMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	ks := keystore{
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          keystoreKDF,
+			KDFParams: keystoreKDFParams{
+				PRF:   keystorePRF,
+				DKLen: keystoreKeyLen,
+				Salt:  hex.EncodeToString(salt),
+				C:     keystoreIterations,
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		Version: 1,
+	}
+	return json.Marshal(ks)
+}
+
+// DecryptKeystore decrypt the keystore with the given passphrase to get the secp256k1 private key
+func DecryptKeystore(buf []byte, passphrase string) (tcrypto.PrivKey, error) {
+	var ks keystore
+	if err := json.Unmarshal(buf, &ks); err != nil {
+		return nil, fmt.Errorf("fail to unmarshal the keystore: %w", err)
+	}
+	c := ks.Crypto
+	if c.Cipher != keystoreCipher || c.KDF != keystoreKDF || c.KDFParams.PRF != keystorePRF {
+		return nil, fmt.Errorf("unsupported keystore, cipher(%s) kdf(%s) prf(%s)", c.Cipher, c.KDF, c.KDFParams.PRF)
+	}
+	if c.KDFParams.DKLen != keystoreKeyLen || c.KDFParams.C <= 0 {
+		return nil, errors.New("invalid kdf parameters of the keystore")
+	}
+	salt, err := hex.DecodeString(c.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the salt: %w", err)
+	}
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the iv: %w", err)
+	}
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the cipher text: %w", err)
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the mac: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid iv of the keystore")
+	}
+	derivedKey := pbkdf2.Key([]byte(passphrase), salt, c.KDFParams.C, keystoreKeyLen, sha256.New)
+	expectedMAC, err := keystoreMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, expectedMAC) {
+		return nil, ErrKeystorePassphrase
+	}
+	rawBytes, err := aesCTRXOR(derivedKey, cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawBytes) != 32 {
+		return nil, fmt.Errorf("invalid private key length(%d)", len(rawBytes))
+	}
+	var keyBytesArray [32]byte
+	copy(keyBytesArray[:], rawBytes)
+	return secp256k1.PrivKeySecp256k1(keyBytesArray), nil
+}
+
+// LoadKeystore read the keystore file and decrypt it with the given passphrase
+func LoadKeystore(path, passphrase string) (tcrypto.PrivKey, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the keystore file(%s): %w", path, err)
+	}
+	return DecryptKeystore(buf, passphrase)
+}
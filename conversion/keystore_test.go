@@ -0,0 +1,42 @@
+package conversion
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+type KeystoreTestSuite struct{}
+
+var _ = Suite(&KeystoreTestSuite{})
+
+func (*KeystoreTestSuite) TestKeystore(c *C) {
+	priKey, err := GetPriKey(testPriKey)
+	c.Assert(err, IsNil)
+	buf, err := EncryptKeystore(priKey, "passphrase")
+	c.Assert(err, IsNil)
+
+	decrypted, err := DecryptKeystore(buf, "passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(decrypted.Equals(priKey), Equals, true)
+	_, err = DecryptKeystore(buf, "wrong passphrase")
+	c.Assert(errors.Is(err, ErrKeystorePassphrase), Equals, true)
+	_, err = DecryptKeystore([]byte("whatever"), "passphrase")
+	c.Assert(err, NotNil)
+
+	folder, err := ioutil.TempDir("", "keystore")
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(os.RemoveAll(folder), IsNil)
+	}()
+	path := filepath.Join(folder, "keystore.json")
+	c.Assert(ioutil.WriteFile(path, buf, 0600), IsNil)
+	loaded, err := LoadKeystore(path, "passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(loaded.Equals(priKey), Equals, true)
+	_, err = LoadKeystore(filepath.Join(folder, "missing.json"), "passphrase")
+	c.Assert(err, NotNil)
+}
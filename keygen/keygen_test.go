@@ -8,7 +8,6 @@ import (
 	"os"
 	"path"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -29,6 +28,7 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/messages"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 	"gitlab.com/thorchain/tss/go-tss/storage"
+	"gitlab.com/thorchain/tss/go-tss/testutil"
 )
 
 var (
@@ -92,14 +92,6 @@ func (s *TssKeygenTestSuite) SetUpSuite(c *C) {
 	}
 }
 
-func (s *TssKeygenTestSuite) TearDownSuite(c *C) {
-	for i, _ := range s.comms {
-		tempFilePath := path.Join(os.TempDir(), strconv.Itoa(i))
-		err := os.RemoveAll(tempFilePath)
-		c.Assert(err, IsNil)
-	}
-}
-
 // SetUpTest set up environment for test key gen
 func (s *TssKeygenTestSuite) SetUpTest(c *C) {
 	ports := []int{
@@ -129,10 +121,7 @@ func (s *TssKeygenTestSuite) SetUpTest(c *C) {
 	}
 
 	for i := 0; i < s.partyNum; i++ {
-		baseHome := path.Join(os.TempDir(), strconv.Itoa(i))
-		fMgr, err := storage.NewFileStateMgr(baseHome)
-		c.Assert(err, IsNil)
-		s.stateMgrs[i] = fMgr
+		s.stateMgrs[i] = testutil.NewMemStateMgr()
 	}
 }
 
@@ -148,20 +137,45 @@ func getPreparams(c *C) []*btsskeygen.LocalPreParams {
 		testFileLocation = "../test_data"
 		preParamTestFile = "preParam_test.data"
 	)
-	var preParamArray []*btsskeygen.LocalPreParams
-	buf, err := ioutil.ReadFile(path.Join(testFileLocation, preParamTestFile))
+	preParamArray, err := LoadPreParamsFromFile(path.Join(testFileLocation, preParamTestFile))
 	c.Assert(err, IsNil)
-	preParamsStr := strings.Split(string(buf), "\n")
-	for _, item := range preParamsStr {
-		var preParam btsskeygen.LocalPreParams
-		val, err := hex.DecodeString(item)
-		c.Assert(err, IsNil)
-		c.Assert(json.Unmarshal(val, &preParam), IsNil)
-		preParamArray = append(preParamArray, &preParam)
-	}
 	return preParamArray
 }
 
+func (s *TssKeygenTestSuite) TestLoadPreParams(c *C) {
+	_, err := LoadPreParams([]byte(""))
+	c.Assert(err, NotNil)
+	_, err = LoadPreParams([]byte("whatever"))
+	c.Assert(err, NotNil)
+	_, err = LoadPreParams([]byte(hex.EncodeToString([]byte("{}"))))
+	c.Assert(err, NotNil)
+	_, err = LoadPreParamsFromFile("/path/does/not/exist")
+	c.Assert(err, NotNil)
+}
+
+func (s *TssKeygenTestSuite) TestSavePreParams(c *C) {
+	filePath := path.Join(c.MkDir(), "preparams.data")
+	c.Assert(SavePreParams(filePath, s.preParams[:2]...), IsNil)
+	loaded, err := LoadPreParamsFromFile(filePath)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, HasLen, 2)
+	c.Assert(loaded[0].NTildei.Cmp(s.preParams[0].NTildei), Equals, 0)
+	c.Assert(loaded[1].PaillierSK.N.Cmp(s.preParams[1].PaillierSK.N), Equals, 0)
+	// the file is replaced as a whole, and no temporary file is left behind
+	c.Assert(SavePreParams(filePath, s.preParams[2]), IsNil)
+	loaded, err = LoadPreParamsFromFile(filePath)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, HasLen, 1)
+	c.Assert(loaded[0].NTildei.Cmp(s.preParams[2].NTildei), Equals, 0)
+	files, err := ioutil.ReadDir(path.Dir(filePath))
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	info, err := os.Stat(filePath)
+	c.Assert(err, IsNil)
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0600))
+	c.Assert(SavePreParams("/path/does/not/exist/preparams.data", s.preParams[0]), NotNil)
+}
+
 func (s *TssKeygenTestSuite) TestGenerateNewKey(c *C) {
 	sort.Strings(testPubKeys)
 	req := NewRequest(testPubKeys)
@@ -197,10 +211,12 @@ func (s *TssKeygenTestSuite) TestGenerateNewKey(c *C) {
 			comm.SetSubscribe(messages.TSSKeyGenVerMsg, messageID, keygenMsgChannel)
 			comm.SetSubscribe(messages.TSSControlMsg, messageID, keygenMsgChannel)
 			comm.SetSubscribe(messages.TSSTaskDone, messageID, keygenMsgChannel)
+			comm.SetSubscribe(messages.TSSUnicastAck, messageID, keygenMsgChannel)
 			defer comm.CancelSubscribe(messages.TSSKeyGenMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSKeyGenVerMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			defer comm.CancelSubscribe(messages.TSSUnicastAck, messageID)
 			resp, err := keygenInstance.GenerateNewKey(req)
 			c.Assert(err, IsNil)
 			lock.Lock()
@@ -250,10 +266,12 @@ func (s *TssKeygenTestSuite) TestGenerateNewKeyWithStop(c *C) {
 			comm.SetSubscribe(messages.TSSKeyGenVerMsg, messageID, keygenMsgChannel)
 			comm.SetSubscribe(messages.TSSControlMsg, messageID, keygenMsgChannel)
 			comm.SetSubscribe(messages.TSSTaskDone, messageID, keygenMsgChannel)
+			comm.SetSubscribe(messages.TSSUnicastAck, messageID, keygenMsgChannel)
 			defer comm.CancelSubscribe(messages.TSSKeyGenMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSKeyGenVerMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			defer comm.CancelSubscribe(messages.TSSUnicastAck, messageID)
 			if idx == 1 {
 				go func() {
 					time.Sleep(time.Millisecond * 200)
@@ -285,6 +303,22 @@ func (s *TssKeygenTestSuite) TestKeyGenWithError(c *C) {
 	c.Assert(generatedKey, IsNil)
 }
 
+func (s *TssKeygenTestSuite) TestKeyGenWithInvalidBackup(c *C) {
+	conf := common.TssConfig{}
+	stateManager := &storage.MockLocalStateManager{}
+	keyGenInstance := NewTssKeyGen("", conf, "", nil, nil, nil, "test", stateManager, s.nodePrivKeys[0], nil)
+	req := Request{
+		Keys:          testPubKeys[:],
+		SkipLocalSave: true,
+	}
+	_, err := keyGenInstance.GenerateNewKey(req)
+	c.Assert(err, Equals, ErrNoBackupPubKey)
+	req.BackupPubKey = "whatever"
+	_, err = keyGenInstance.GenerateNewKey(req)
+	c.Assert(err, ErrorMatches, "invalid backup pub key.*")
+	c.Assert(keyGenInstance.GetEncryptedShare(), IsNil)
+}
+
 func (s *TssKeygenTestSuite) TestCloseKeyGennotifyChannel(c *C) {
 	conf := common.TssConfig{}
 	stateManager := &storage.MockLocalStateManager{}
@@ -0,0 +1,95 @@
+package keygen
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+)
+
+// LoadPreParams parse the pre-parameters from the given data, one hex encoded json pre-parameter per line.
+// Together with a fixed committee it lets the integrators replay the keygen without generating the safe primes,
+// note that the pinned tss-lib draws its randomness from crypto/rand, so the generated key still differs between runs
+func LoadPreParams(data []byte) ([]*bkg.LocalPreParams, error) {
+	var result []*bkg.LocalPreParams
+	for i, item := range strings.Split(string(data), "\n") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		buf, err := hex.DecodeString(item)
+		if err != nil {
+			return nil, fmt.Errorf("fail to decode pre-parameter at line %d: %w", i+1, err)
+		}
+		var preParam bkg.LocalPreParams
+		if err := json.Unmarshal(buf, &preParam); err != nil {
+			return nil, fmt.Errorf("fail to unmarshal pre-parameter at line %d: %w", i+1, err)
+		}
+		if !preParam.Validate() {
+			return nil, fmt.Errorf("invalid pre-parameter at line %d", i+1)
+		}
+		result = append(result, &preParam)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no pre-parameter found")
+	}
+	return result, nil
+}
+
+// LoadPreParamsFromFile read the pre-parameters from the given file
+func LoadPreParamsFromFile(filePath string) ([]*bkg.LocalPreParams, error) {
+	buf, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the pre-parameter file: %w", err)
+	}
+	return LoadPreParams(buf)
+}
+
+// SavePreParams write the pre-parameters to the given file in the format LoadPreParams reads, the file is written
+// to a temporary file first and renamed, so a crash never leaves a truncated file behind
+func SavePreParams(filePath string, preParams ...*bkg.LocalPreParams) error {
+	var sb strings.Builder
+	for _, el := range preParams {
+		buf, err := json.Marshal(el)
+		if err != nil {
+			return fmt.Errorf("fail to marshal the pre-parameter: %w", err)
+		}
+		sb.WriteString(hex.EncodeToString(buf))
+		sb.WriteString("\n")
+	}
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return fmt.Errorf("fail to create the temporary pre-parameter file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	if err := writePreParamFile(tmpFile, sb.String()); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("fail to rename the pre-parameter file: %w", err)
+	}
+	return nil
+}
+
+func writePreParamFile(f *os.File, content string) error {
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fail to write the pre-parameter file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fail to sync the pre-parameter file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("fail to close the pre-parameter file: %w", err)
+	}
+	return nil
+}
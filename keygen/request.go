@@ -1,8 +1,19 @@
 package keygen
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCommitteeTooSmall is returned when fewer nodes join the keygen than the request allows to proceed with
+var ErrCommitteeTooSmall = errors.New("not enough nodes joined the keygen")
+
 // Request request to do keygen
 type Request struct {
-	Keys []string `json:"keys"`
+	Keys          []string `json:"keys"`
+	BackupPubKey  string   `json:"backup_pub_key,omitempty"`  // optional, return the share encrypted to this pub key
+	SkipLocalSave bool     `json:"skip_local_save,omitempty"` // do not save the share locally, requires the backup pub key
+	MinParties    int      `json:"min_parties,omitempty"`     // optional, proceed with the nodes that joined if at least this many did
 }
 
 // NewRequest creeate a new instance of keygen.Request
@@ -11,3 +22,30 @@ func NewRequest(keys []string) Request {
 		Keys: keys,
 	}
 }
+
+// AllowPartialCommittee check whether the keygen may proceed without all the nodes of the request
+func (r Request) AllowPartialCommittee() bool {
+	return r.MinParties > 0 && r.MinParties < len(r.Keys)
+}
+
+// PartialCommittee return the keys of the request that are online, in the order of the request, it fails if
+// fewer than MinParties of them are online
+func (r Request) PartialCommittee(onlineKeys []string) ([]string, error) {
+	if r.MinParties < 2 {
+		return nil, fmt.Errorf("invalid min parties(%d), at least 2 parties are required", r.MinParties)
+	}
+	online := make(map[string]bool, len(onlineKeys))
+	for _, el := range onlineKeys {
+		online[el] = true
+	}
+	var committee []string
+	for _, el := range r.Keys {
+		if online[el] {
+			committee = append(committee, el)
+		}
+	}
+	if len(committee) < r.MinParties {
+		return nil, fmt.Errorf("%d out of %d nodes joined, %d are required: %w", len(committee), len(r.Keys), r.MinParties, ErrCommitteeTooSmall)
+	}
+	return committee, nil
+}
@@ -0,0 +1,31 @@
+package keygen
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+)
+
+type RequestTestSuite struct{}
+
+var _ = Suite(&RequestTestSuite{})
+
+func (RequestTestSuite) TestPartialCommittee(c *C) {
+	keys := []string{"key1", "key2", "key3", "key4"}
+	req := NewRequest(keys)
+	c.Assert(req.AllowPartialCommittee(), Equals, false)
+	_, err := req.PartialCommittee(keys[:3])
+	c.Assert(err, NotNil)
+
+	req.MinParties = 4
+	c.Assert(req.AllowPartialCommittee(), Equals, false)
+	req.MinParties = 3
+	c.Assert(req.AllowPartialCommittee(), Equals, true)
+
+	committee, err := req.PartialCommittee([]string{"key4", "key1", "key2", "unknown"})
+	c.Assert(err, IsNil)
+	c.Assert(committee, DeepEquals, []string{"key1", "key2", "key4"})
+
+	_, err = req.PartialCommittee([]string{"key1", "key2"})
+	c.Assert(errors.Is(err, ErrCommitteeTooSmall), Equals, true)
+}
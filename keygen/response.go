@@ -11,6 +11,11 @@ type Response struct {
 	PoolAddress string        `json:"pool_address"`
 	Status      common.Status `json:"status"`
 	Blame       blame.Blame   `json:"blame"`
+	// EncryptedShare is the base64 encoded share of this node encrypted to the backup pub key of the request
+	EncryptedShare string `json:"encrypted_share,omitempty"`
+	// Committee is the keys of the nodes that generate the key, it is only set when the keygen proceeds without
+	// all the nodes of the request
+	Committee []string `json:"committee,omitempty"`
 }
 
 // NewResponse create a new instance of keygen.Response
@@ -1,8 +1,10 @@
 package keygen
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +23,9 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/storage"
 )
 
+// ErrNoBackupPubKey is returned when the keygen request asks us not to save the share without giving a backup pub key
+var ErrNoBackupPubKey = errors.New("skip local save requires a backup pub key, otherwise the share is lost")
+
 type TssKeyGen struct {
 	logger          zerolog.Logger
 	localNodePubKey string
@@ -31,6 +36,8 @@ type TssKeyGen struct {
 	stateManager    storage.LocalStateManager
 	commStopChan    chan struct{}
 	p2pComm         *p2p.Communication
+	encryptedShare  []byte
+	deadline        time.Time
 }
 
 func NewTssKeyGen(localP2PID string,
@@ -66,7 +73,25 @@ func (tKeyGen *TssKeyGen) GetTssCommonStruct() *common.TssCommon {
 	return tKeyGen.tssCommonStruct
 }
 
+// SetDeadline set the time by which the keygen has to finish, the zero time means no deadline
+func (tKeyGen *TssKeyGen) SetDeadline(deadline time.Time) {
+	tKeyGen.deadline = deadline
+}
+
+// GetEncryptedShare return the share encrypted to the backup pub key, it is nil if no backup is requested
+func (tKeyGen *TssKeyGen) GetEncryptedShare() []byte {
+	return tKeyGen.encryptedShare
+}
+
 func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, error) {
+	if keygenReq.SkipLocalSave && len(keygenReq.BackupPubKey) == 0 {
+		return nil, ErrNoBackupPubKey
+	}
+	if len(keygenReq.BackupPubKey) != 0 {
+		if _, err := conversion.ParseSecp256PubKey(keygenReq.BackupPubKey); err != nil {
+			return nil, fmt.Errorf("invalid backup pub key: %w", err)
+		}
+	}
 	partiesID, localPartyID, err := conversion.GetParties(keygenReq.Keys, tKeyGen.localNodePubKey)
 	if err != nil {
 		return nil, fmt.Errorf("fail to get keygen parties: %w", err)
@@ -81,6 +106,14 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 	if err != nil {
 		return nil, err
 	}
+	// we bail out before the ceremony starts if we are not able to save the result, so that the committee
+	// fails together rather than ending up with the key share missing on this node
+	if !keygenReq.SkipLocalSave {
+		if err := tKeyGen.stateManager.CheckFreeSpace(storage.EstimateLocalStateSize(len(partiesID))); err != nil {
+			tKeyGen.logger.Error().Err(err).Msg("not enough space to save the keygen result")
+			return nil, fmt.Errorf("fail to start keygen: %w", err)
+		}
+	}
 	ctx := btss.NewPeerContext(partiesID)
 	params := btss.NewParameters(ctx, localPartyID, len(partiesID), threshold)
 	outCh := make(chan btss.Message, len(partiesID))
@@ -121,7 +154,7 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 	}()
 	go tKeyGen.tssCommonStruct.ProcessInboundMessages(tKeyGen.commStopChan, &keyGenWg)
 
-	r, err := tKeyGen.processKeyGen(errChan, outCh, endCh, keyGenLocalStateItem)
+	r, err := tKeyGen.processKeyGen(errChan, outCh, endCh, keyGenLocalStateItem, keygenReq)
 	if err != nil {
 		close(tKeyGen.commStopChan)
 		return nil, fmt.Errorf("fail to process key sign: %w", err)
@@ -141,11 +174,14 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 	outCh <-chan btss.Message,
 	endCh <-chan bkg.LocalPartySaveData,
-	keyGenLocalStateItem storage.KeygenLocalState) (*bcrypto.ECPoint, error) {
+	keyGenLocalStateItem storage.KeygenLocalState,
+	keygenReq Request) (*bcrypto.ECPoint, error) {
 	defer tKeyGen.logger.Debug().Msg("finished keygen process")
 	tKeyGen.logger.Debug().Msg("start to read messages from local party")
 	tssConf := tKeyGen.tssCommonStruct.GetConf()
 	blameMgr := tKeyGen.tssCommonStruct.GetBlameMgr()
+	deadline, stopDeadline := common.DeadlineTimer(tKeyGen.deadline)
+	defer stopDeadline()
 	for {
 		select {
 		case <-errChan: // when keyGenParty return
@@ -155,51 +191,27 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 		case <-tKeyGen.stopChan: // when TSS processor receive signal to quit
 			return nil, errors.New("received exit signal")
 
-		case <-time.After(tssConf.KeyGenTimeout):
+		case <-time.After(tssConf.KeyGenProtocolTimeout()):
 			// we bail out after KeyGenTimeoutSeconds
-			tKeyGen.logger.Error().Msgf("fail to generate message with %s", tssConf.KeyGenTimeout.String())
-			lastMsg := blameMgr.GetLastMsg()
-			failReason := blameMgr.GetBlame().FailReason
-			if failReason == "" {
-				failReason = blame.TssTimeout
-			}
-			if lastMsg == nil {
-				tKeyGen.logger.Error().Msg("fail to start the keygen, the last produced message of this node is none")
-				return nil, errors.New("timeout before shared message is generated")
-			}
-			blameNodesUnicast, err := blameMgr.GetUnicastBlame(messages.KEYGEN2aUnicast)
-			if err != nil {
-				tKeyGen.logger.Error().Err(err).Msg("error in get unicast blame")
-			}
-			threshold, err := common.GetThreshold(len(tKeyGen.tssCommonStruct.P2PPeers) + 1)
-			if err != nil {
-				tKeyGen.logger.Error().Err(err).Msg("error in get the threshold to generate blame")
-			}
+			tKeyGen.logger.Error().Msgf("fail to generate message with %s", tssConf.KeyGenProtocolTimeout().String())
+			return nil, tKeyGen.processTimeout()
 
-			if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
-				blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
-			}
-			blameNodesBroadcast, err := blameMgr.GetBroadcastBlame(lastMsg.Type())
-			if err != nil {
-				tKeyGen.logger.Error().Err(err).Msg("error in get broadcast blame")
-			}
-			blameMgr.GetBlame().AddBlameNodes(blameNodesBroadcast...)
-
-			// if we cannot find the blame node, we check whether everyone send me the share
-			if len(blameMgr.GetBlame().BlameNodes) == 0 {
-				blameNodesMisingShare, isUnicast, err := blameMgr.TssMissingShareBlame(messages.TSSKEYGENROUNDS)
-				if err != nil {
-					tKeyGen.logger.Error().Err(err).Msg("fail to get the node of missing share ")
-				}
-				if len(blameNodesMisingShare) > 0 && len(blameNodesMisingShare) <= threshold {
-					blameMgr.GetBlame().AddBlameNodes(blameNodesMisingShare...)
-					blameMgr.GetBlame().IsUnicast = isUnicast
-				}
-			}
-			return nil, blame.ErrTssTimeOut
+		case <-deadline:
+			tKeyGen.logger.Error().Msgf("keygen is not done within %s", tssConf.MaxCeremonyDuration)
+			return nil, tKeyGen.processTimeout()
 
 		case msg := <-outCh:
 			tKeyGen.logger.Debug().Msgf(">>>>>>>>>>msg: %s", msg.String())
+			// the last round message is the point of no return for our peers, we check the space again
+			// before we send it out, in case the state folder fills up during the ceremony
+			if !keygenReq.SkipLocalSave && strings.HasSuffix(msg.Type(), messages.KEYGEN3) {
+				required := storage.EstimateLocalStateSize(len(keyGenLocalStateItem.ParticipantKeys))
+				if err := tKeyGen.stateManager.CheckFreeSpace(required); err != nil {
+					tKeyGen.logger.Error().Err(err).Msg("abort keygen, not enough space to save the keygen result")
+					blameMgr.GetBlame().SetBlame(blame.StorageFail, []blame.Node{blame.NewNode(tKeyGen.localNodePubKey, nil, nil)}, false)
+					return nil, fmt.Errorf("fail to save keygen result: %w", err)
+				}
+			}
 			blameMgr.SetLastMsg(msg)
 			err := tKeyGen.tssCommonStruct.ProcessOutCh(msg, messages.TSSKeyGenMsg)
 			if err != nil {
@@ -219,8 +231,16 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 			}
 			keyGenLocalStateItem.LocalData = msg
 			keyGenLocalStateItem.PubKey = pubKey
-			if err := tKeyGen.stateManager.SaveLocalState(keyGenLocalStateItem); err != nil {
-				return nil, fmt.Errorf("fail to save keygen result to storage: %w", err)
+			keyGenLocalStateItem.CreatedAt = time.Now().UTC()
+			if len(keygenReq.BackupPubKey) != 0 {
+				if err := tKeyGen.encryptShare(keyGenLocalStateItem, keygenReq.BackupPubKey); err != nil {
+					return nil, err
+				}
+			}
+			if !keygenReq.SkipLocalSave {
+				if err := tKeyGen.stateManager.SaveLocalState(keyGenLocalStateItem); err != nil {
+					return nil, fmt.Errorf("fail to save keygen result to storage: %w", err)
+				}
 			}
 			address := tKeyGen.p2pComm.ExportPeerAddress()
 			if err := tKeyGen.stateManager.SaveAddressBook(address); err != nil {
@@ -230,3 +250,61 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 		}
 	}
 }
+
+// processTimeout work out the nodes to blame when the keygen times out
+func (tKeyGen *TssKeyGen) processTimeout() error {
+	blameMgr := tKeyGen.tssCommonStruct.GetBlameMgr()
+	lastMsg := blameMgr.GetLastMsg()
+	failReason := blameMgr.GetBlame().FailReason
+	if failReason == "" {
+		failReason = blame.TssTimeout
+	}
+	if lastMsg == nil {
+		tKeyGen.logger.Error().Msg("fail to start the keygen, the last produced message of this node is none")
+		return errors.New("timeout before shared message is generated")
+	}
+	blameNodesUnicast, err := blameMgr.GetUnicastBlame(messages.KEYGEN2aUnicast)
+	if err != nil {
+		tKeyGen.logger.Error().Err(err).Msg("error in get unicast blame")
+	}
+	threshold, err := common.GetThreshold(len(tKeyGen.tssCommonStruct.P2PPeers) + 1)
+	if err != nil {
+		tKeyGen.logger.Error().Err(err).Msg("error in get the threshold to generate blame")
+	}
+
+	if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
+		blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
+	}
+	blameNodesBroadcast, err := blameMgr.GetBroadcastBlame(lastMsg.Type())
+	if err != nil {
+		tKeyGen.logger.Error().Err(err).Msg("error in get broadcast blame")
+	}
+	blameMgr.GetBlame().AddBlameNodes(blameNodesBroadcast...)
+
+	// if we cannot find the blame node, we check whether everyone send me the share
+	if len(blameMgr.GetBlame().BlameNodes) == 0 {
+		blameNodesMisingShare, isUnicast, err := blameMgr.TssMissingShareBlame(messages.TSSKEYGENROUNDS)
+		if err != nil {
+			tKeyGen.logger.Error().Err(err).Msg("fail to get the node of missing share ")
+		}
+		if len(blameNodesMisingShare) > 0 && len(blameNodesMisingShare) <= threshold {
+			blameMgr.GetBlame().AddBlameNodes(blameNodesMisingShare...)
+			blameMgr.GetBlame().IsUnicast = isUnicast
+		}
+	}
+	return blame.ErrTssTimeOut
+}
+
+// encryptShare encrypt the keygen result to the backup pub key, so that the orchestrator can archive it
+func (tKeyGen *TssKeyGen) encryptShare(state storage.KeygenLocalState, backupPubKey string) error {
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the keygen result: %w", err)
+	}
+	encrypted, err := conversion.EncryptToPubKey(backupPubKey, buf)
+	if err != nil {
+		return fmt.Errorf("fail to encrypt the keygen result: %w", err)
+	}
+	tKeyGen.encryptedShare = encrypted
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -85,6 +86,14 @@ func (m *MockLocalStateManager) GetLocalState(pubKey string) (storage.KeygenLoca
 	return state, nil
 }
 
+func (m *MockLocalStateManager) ListLocalStates() ([]storage.KeygenLocalState, error) {
+	state, err := m.GetLocalState("")
+	if err != nil {
+		return nil, err
+	}
+	return []storage.KeygenLocalState{state}, nil
+}
+
 func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	return nil
 }
@@ -93,6 +102,19 @@ func (s *MockLocalStateManager) RetrieveP2PAddresses() (addr.AddrList, error) {
 	return nil, os.ErrNotExist
 }
 
+func (s *MockLocalStateManager) CheckFreeSpace(required uint64) error {
+	return nil
+}
+
+func (m *MockLocalStateManager) GetKeyMetadata(pubKey string) ([]string, int, time.Time, error) {
+	state, err := m.GetLocalState(pubKey)
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	threshold, err := common.GetThreshold(len(state.ParticipantKeys))
+	return state.ParticipantKeys, threshold, state.CreatedAt, err
+}
+
 type TssKeysignTestSuite struct {
 	comms        []*p2p.Communication
 	partyNum     int
@@ -198,10 +220,12 @@ func (s *TssKeysignTestSuite) TestSignMessage(c *C) {
 			comm.SetSubscribe(messages.TSSKeySignVerMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSControlMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSTaskDone, messageID, keysignMsgChannel)
+			comm.SetSubscribe(messages.TSSUnicastAck, messageID, keysignMsgChannel)
 			defer comm.CancelSubscribe(messages.TSSKeySignMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSKeySignVerMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			defer comm.CancelSubscribe(messages.TSSUnicastAck, messageID)
 
 			localState, err := s.stateMgrs[idx].GetLocalState(req.PoolPubKey)
 			c.Assert(err, IsNil)
@@ -280,10 +304,12 @@ func (s *TssKeysignTestSuite) TestSignMessageWithStop(c *C) {
 			comm.SetSubscribe(messages.TSSKeySignVerMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSControlMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSTaskDone, messageID, keysignMsgChannel)
+			comm.SetSubscribe(messages.TSSUnicastAck, messageID, keysignMsgChannel)
 			defer comm.CancelSubscribe(messages.TSSKeySignMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSKeySignVerMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			defer comm.CancelSubscribe(messages.TSSUnicastAck, messageID)
 
 			localState, err := s.stateMgrs[idx].GetLocalState(req.PoolPubKey)
 			c.Assert(err, IsNil)
@@ -361,10 +387,12 @@ func (s *TssKeysignTestSuite) TestSignMessageRejectOnePeer(c *C) {
 			comm.SetSubscribe(messages.TSSKeySignVerMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSControlMsg, messageID, keysignMsgChannel)
 			comm.SetSubscribe(messages.TSSTaskDone, messageID, keysignMsgChannel)
+			comm.SetSubscribe(messages.TSSUnicastAck, messageID, keysignMsgChannel)
 			defer comm.CancelSubscribe(messages.TSSKeySignMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSKeySignVerMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
 			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			defer comm.CancelSubscribe(messages.TSSUnicastAck, messageID)
 
 			localState, err := s.stateMgrs[idx].GetLocalState(req.PoolPubKey)
 			c.Assert(err, IsNil)
@@ -380,6 +408,29 @@ func (s *TssKeysignTestSuite) TestSignMessageRejectOnePeer(c *C) {
 	wg.Wait()
 }
 
+func (s *TssKeysignTestSuite) TestSignMessageInsufficientSigners(c *C) {
+	if testing.Short() {
+		c.Skip("skip the test")
+		return
+	}
+	req := NewRequest("thorpub1addwnpepqv6xp3fmm47dfuzglywqvpv8fdjv55zxte4a26tslcezns5czv586u2fw33", "helloworld-test111", nil)
+	localState, err := s.stateMgrs[0].GetLocalState(req.PoolPubKey)
+	c.Assert(err, IsNil)
+	// only the local party and one more peer are left after the filtering, the threshold of 4 parties is 2
+	signers := []string{localState.LocalPartyKey}
+	for _, el := range localState.ParticipantKeys {
+		if el != localState.LocalPartyKey {
+			signers = append(signers, el)
+			break
+		}
+	}
+	conf := common.TssConfig{}
+	keysignIns := NewTssKeySign(s.comms[0].GetLocalPeerID(), conf, s.comms[0].BroadcastMsgChan, make(chan struct{}),
+		"insufficient", s.nodePrivKeys[0], s.comms[0], s.stateMgrs[0])
+	_, err = keysignIns.SignMessage([]byte(req.Message), localState, signers)
+	c.Assert(errors.Is(err, ErrInsufficientSigners), Equals, true)
+}
+
 func (s *TssKeysignTestSuite) TearDownSuite(c *C) {
 	for i, _ := range s.comms {
 		tempFilePath := path.Join(os.TempDir(), strconv.Itoa(i))
@@ -427,3 +478,10 @@ func (s *TssKeysignTestSuite) TestCloseKeySignnotifyChannel(c *C) {
 	err = keySignInstance.tssCommonStruct.ProcessOneMessage(msg, "node1")
 	c.Assert(err, ErrorMatches, "duplicated notification from peer node1 ignored")
 }
+
+func (s *TssKeysignTestSuite) TestProcessTimeoutWithoutLastMsg(c *C) {
+	conf := common.TssConfig{}
+	keySignInstance := NewTssKeySign("", conf, nil, nil, "test", s.nodePrivKeys[0], s.comms[0], s.stateMgrs[0])
+	// the deadline can fire before the local party produces any message
+	c.Assert(keySignInstance.processTimeout(), NotNil)
+}
@@ -1,6 +1,7 @@
 package keysign
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 
 	bc "github.com/binance-chain/tss-lib/common"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/tendermint/btcd/btcec"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
@@ -17,6 +19,9 @@ type Notifier struct {
 	MessageID  string
 	message    []byte // the message
 	poolPubKey string
+	quorum     int // how many distinct signers should send us the same valid signature
+	signers    map[peer.ID]bool
+	signature  *bc.SignatureData
 	resp       chan *bc.SignatureData
 }
 
@@ -35,10 +40,20 @@ func NewNotifier(messageID string, message []byte, poolPubKey string) (*Notifier
 		MessageID:  messageID,
 		message:    message,
 		poolPubKey: poolPubKey,
+		quorum:     1,
+		signers:    make(map[peer.ID]bool),
 		resp:       make(chan *bc.SignatureData, 1),
 	}, nil
 }
 
+// SetQuorum set how many distinct signers should send us the same valid signature before we accept it
+func (n *Notifier) SetQuorum(quorum int) {
+	if quorum < 1 {
+		quorum = 1
+	}
+	n.quorum = quorum
+}
+
 // verifySignature is a method to verify the signature against the message it signed , if the signature can be verified successfully
 // There is a method call VerifyBytes in crypto.PubKey, but we can't use that method to verify the signature, because it always hash the message
 // first and then verify the hash of the message against the signature , which is not the case in tss
@@ -60,20 +75,31 @@ func (n *Notifier) verifySignature(data *bc.SignatureData) (bool, error) {
 // ProcessSignature is to verify whether the signature is valid
 // return value bool , true indicated we already gather all the signature from keysign party, and they are all match
 // false means we are still waiting for more signature from keysign party
-func (n *Notifier) ProcessSignature(data *bc.SignatureData) (bool, error) {
+func (n *Notifier) ProcessSignature(signer peer.ID, data *bc.SignatureData) (bool, error) {
 	// only need to verify the signature when data is not nil
 	// when data is nil , which means keysign  failed, there is no signature to be verified in that case
-	if data != nil {
-		verify, err := n.verifySignature(data)
-		if err != nil {
-			return false, fmt.Errorf("fail to verify signature: %w", err)
-		}
-		if !verify {
-			return false, nil
-		}
+	if data == nil {
+		// it is ok to push nil to the resp channel , the receiver will check it
+		n.resp <- data
+		return true, nil
+	}
+	verify, err := n.verifySignature(data)
+	if err != nil {
+		return false, fmt.Errorf("fail to verify signature: %w", err)
+	}
+	if !verify {
+		return false, nil
+	}
+	if n.signature == nil {
+		n.signature = data
+	} else if !bytes.Equal(n.signature.R, data.R) || !bytes.Equal(n.signature.S, data.S) {
+		return false, fmt.Errorf("signature from %s does not match the one we received", signer)
+	}
+	n.signers[signer] = true
+	if len(n.signers) < n.quorum {
+		return false, nil
 	}
-	// it is ok to push nil to the resp channel , the receiver will check it
-	n.resp <- data
+	n.resp <- n.signature
 	return true, nil
 }
 
@@ -65,11 +65,12 @@ func (NotifierTestSuite) TestNotifierHappyPath(c *C) {
 	var sigInvalid bc.SignatureData
 	c.Assert(json.Unmarshal(contentInvalid, &sigInvalid), IsNil)
 	// valid keysign peer , but invalid signature we should continue to listen
-	finish, err := n.ProcessSignature(&sigInvalid)
+	signer := conversion.GetRandomPeerID()
+	finish, err := n.ProcessSignature(signer, &sigInvalid)
 	c.Assert(err, IsNil)
 	c.Assert(finish, Equals, false)
 	// valid signature from a keysign peer , we should accept it and bail out
-	finish, err = n.ProcessSignature(&signature)
+	finish, err = n.ProcessSignature(signer, &signature)
 	c.Assert(err, IsNil)
 	c.Assert(finish, Equals, true)
 
@@ -77,3 +78,34 @@ func (NotifierTestSuite) TestNotifierHappyPath(c *C) {
 	c.Assert(result, NotNil)
 	c.Assert(&signature == result, Equals, true)
 }
+
+func (NotifierTestSuite) TestNotifierQuorum(c *C) {
+	messageToSign := "yhEwrxWuNBGnPT/L7PNnVWg7gFWNzCYTV+GuX3tKRH8="
+	buf, err := base64.StdEncoding.DecodeString(messageToSign)
+	c.Assert(err, IsNil)
+	messageID, err := common.MsgToHashString(buf)
+	c.Assert(err, IsNil)
+	poolPubKey := `thorpub1addwnpepq0ul3xt882a6nm6m7uhxj4tk2n82zyu647dyevcs5yumuadn4uamqx7neak`
+	n, err := NewNotifier(messageID, buf, poolPubKey)
+	c.Assert(err, IsNil)
+	n.SetQuorum(2)
+	content, err := ioutil.ReadFile("../test_data/signature_notify/sig1.json")
+	c.Assert(err, IsNil)
+	var signature bc.SignatureData
+	c.Assert(json.Unmarshal(content, &signature), IsNil)
+
+	signer1 := conversion.GetRandomPeerID()
+	signer2 := conversion.GetRandomPeerID()
+	finish, err := n.ProcessSignature(signer1, &signature)
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, false)
+	// the same signer does not count twice
+	finish, err = n.ProcessSignature(signer1, &signature)
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, false)
+	finish, err = n.ProcessSignature(signer2, &signature)
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, true)
+	result := <-n.GetResponseChannel()
+	c.Assert(result == &signature, Equals, true)
+}
@@ -0,0 +1,17 @@
+package keysign
+
+// Result is the keysign result we publish to the downstream consumers
+type Result struct {
+	// PoolPubKey is the pub key the signature is produced with, it is the child pub key for the derived keysign
+	PoolPubKey  string   `json:"pool_pub_key"`
+	MessageHash string   `json:"message_hash"` // hex encoded sha256 hash of the message that has been signed
+	R           string   `json:"r"`
+	S           string   `json:"s"`
+	Signers     []string `json:"signers"`
+}
+
+// ResultPublisher emits the keysign results to a sink such as a message queue, so the consumers can pick up the
+// signatures asynchronously
+type ResultPublisher interface {
+	Publish(result Result) error
+}
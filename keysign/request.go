@@ -1,10 +1,22 @@
 package keysign
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSignerNotInCommittee is returned when the keysign request asks the nodes that did not take part in the keygen
+// of the pool to sign
+var ErrSignerNotInCommittee = errors.New("signers are not in the keygen committee")
+
 // Request request to sign a message
 type Request struct {
 	PoolPubKey    string   `json:"pool_pub_key"` // pub key of the pool that we would like to send this message from
 	Message       string   `json:"message"`      // base64 encoded message to be signed
 	SignerPubKeys []string `json:"signer_pub_keys"`
+	// DerivationIndex sign with the child key of the given index derived from the pool key instead of the pool key
+	DerivationIndex *uint32 `json:"derivation_index,omitempty"`
 }
 
 func NewRequest(pk, msg string, signers []string) Request {
@@ -14,3 +26,22 @@ func NewRequest(pk, msg string, signers []string) Request {
 		SignerPubKeys: signers,
 	}
 }
+
+// CheckSigners make sure all the signers of the request are members of the keygen committee of the pool, the
+// error lists the signers that are not
+func (r Request) CheckSigners(committee []string) error {
+	members := make(map[string]bool, len(committee))
+	for _, el := range committee {
+		members[el] = true
+	}
+	var offending []string
+	for _, el := range r.SignerPubKeys {
+		if !members[el] {
+			offending = append(offending, el)
+		}
+	}
+	if len(offending) != 0 {
+		return fmt.Errorf("%w: %s", ErrSignerNotInCommittee, strings.Join(offending, ","))
+	}
+	return nil
+}
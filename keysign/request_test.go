@@ -0,0 +1,22 @@
+package keysign
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+)
+
+type RequestTestSuite struct{}
+
+var _ = Suite(&RequestTestSuite{})
+
+func (RequestTestSuite) TestCheckSigners(c *C) {
+	committee := []string{"key1", "key2", "key3", "key4"}
+	req := NewRequest("pool", "bWVzc2FnZQ==", []string{"key3", "key1", "key2"})
+	c.Assert(req.CheckSigners(committee), IsNil)
+
+	req.SignerPubKeys = []string{"key1", "key5", "key2", "key6"}
+	err := req.CheckSigners(committee)
+	c.Assert(errors.Is(err, ErrSignerNotInCommittee), Equals, true)
+	c.Assert(err, ErrorMatches, ".*: key5,key6")
+}
@@ -35,28 +35,46 @@ type SignatureNotifier struct {
 	notifiers    map[string]*Notifier
 	messages     chan *signatureItem
 	streamMgr    *p2p.StreamMgr
+	ctx          context.Context
+	maxPayload   uint32
 }
 
 // NewSignatureNotifier create a new instance of SignatureNotifier
 func NewSignatureNotifier(host host.Host) *SignatureNotifier {
+	return NewSignatureNotifierWithContext(context.Background(), host)
+}
+
+// NewSignatureNotifierWithContext create a new instance of SignatureNotifier, it stops sending and waiting for the
+// signatures once the given context is cancelled
+func NewSignatureNotifierWithContext(ctx context.Context, host host.Host) *SignatureNotifier {
 	s := &SignatureNotifier{
+		ctx:          ctx,
 		logger:       log.With().Str("module", "signature_notifier").Logger(),
 		host:         host,
 		notifierLock: &sync.Mutex{},
 		notifiers:    make(map[string]*Notifier),
 		messages:     make(chan *signatureItem),
 		streamMgr:    p2p.NewStreamMgr(),
+		maxPayload:   p2p.MaxPayload,
 	}
 	host.SetStreamHandler(signatureNotifierProtocol, s.handleStream)
 	return s
 }
 
+// SetMaxPayload set the max payload of the signature notifications we accept, zero restores p2p.MaxPayload
+func (s *SignatureNotifier) SetMaxPayload(size uint32) {
+	if size == 0 {
+		size = p2p.MaxPayload
+	}
+	s.maxPayload = size
+}
+
 // HandleStream handle signature notify stream
 func (s *SignatureNotifier) handleStream(stream network.Stream) {
 	remotePeer := stream.Conn().RemotePeer()
 	logger := s.logger.With().Str("remote peer", remotePeer.String()).Logger()
 	logger.Debug().Msg("reading signature notifier message")
-	payload, err := p2p.ReadStreamWithBuffer(stream)
+	payload, err := p2p.ReadStreamWithBuffer(stream, s.maxPayload)
 	if err != nil {
 		logger.Err(err).Msgf("fail to read payload from stream")
 		s.streamMgr.AddStream("UNKNOWN", stream)
@@ -88,7 +106,7 @@ func (s *SignatureNotifier) handleStream(stream network.Stream) {
 		logger.Debug().Msgf("notifier for message id(%s) not exist", msg.ID)
 		return
 	}
-	finished, err := n.ProcessSignature(&signature)
+	finished, err := n.ProcessSignature(remotePeer, &signature)
 	if err != nil {
 		logger.Error().Err(err).Msg("fail to update local signature data")
 		return
@@ -99,7 +117,7 @@ func (s *SignatureNotifier) handleStream(stream network.Stream) {
 }
 
 func (s *SignatureNotifier) sendOneMsgToPeer(m *signatureItem) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	ctx, cancel := context.WithTimeout(s.ctx, time.Second*30)
 	defer cancel()
 	stream, err := s.host.NewStream(ctx, m.peerID, signatureNotifierProtocol)
 	if err != nil {
@@ -186,12 +204,13 @@ func (s *SignatureNotifier) removeNotifier(n *Notifier) {
 	delete(s.notifiers, n.MessageID)
 }
 
-// WaitForSignature wait until keysign finished and signature is available
-func (s *SignatureNotifier) WaitForSignature(messageID string, message []byte, poolPubKey string, timeout time.Duration) (*bc.SignatureData, error) {
+// WaitForSignature wait until keysign finished and the same signature is received from quorum distinct signers
+func (s *SignatureNotifier) WaitForSignature(messageID string, message []byte, poolPubKey string, timeout time.Duration, quorum int) (*bc.SignatureData, error) {
 	n, err := NewNotifier(messageID, message, poolPubKey)
 	if err != nil {
 		return nil, fmt.Errorf("fail to create notifier")
 	}
+	n.SetQuorum(quorum)
 	s.addToNotifiers(n)
 	defer s.removeNotifier(n)
 
@@ -200,6 +219,8 @@ func (s *SignatureNotifier) WaitForSignature(messageID string, message []byte, p
 		return d, nil
 	case <-time.After(timeout):
 		return nil, fmt.Errorf("timeout: didn't receive signature after %s", timeout)
+	case <-s.ctx.Done():
+		return nil, fmt.Errorf("stop waiting for the signature: %w", s.ctx.Err())
 	}
 }
 
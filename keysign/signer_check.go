@@ -0,0 +1,51 @@
+package keysign
+
+import (
+	"errors"
+	"fmt"
+
+	bc "github.com/binance-chain/tss-lib/common"
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+)
+
+// ErrSignerMismatch indicates the signature is not produced by the key we expect
+var ErrSignerMismatch = errors.New("the pub key recovered from the signature does not match the expected pub key")
+
+// compactSignature encode the signature in the compact format, which starts with the recovery header
+func compactSignature(sig *bc.SignatureData, recoveryID byte) []byte {
+	result := make([]byte, 65)
+	// 27 is the offset of the recovery header and 4 indicates the compressed pub key
+	result[0] = 27 + 4 + recoveryID
+	copy(result[33-len(sig.R):33], sig.R)
+	copy(result[65-len(sig.S):], sig.S)
+	return result
+}
+
+// VerifySigner recover the pub key from the signature and check it is the expected pub key, as the signature does
+// not carry its recovery id, we try all of them
+func VerifySigner(msg []byte, sig *bc.SignatureData, expectedPubKey string) error {
+	if sig == nil || len(sig.R) == 0 || len(sig.R) > 32 || len(sig.S) == 0 || len(sig.S) > 32 {
+		return errors.New("invalid signature")
+	}
+	for recoveryID := byte(0); recoveryID < 4; recoveryID++ {
+		pk, _, err := btcec.RecoverCompact(btcec.S256(), compactSignature(sig, recoveryID), msg)
+		if err != nil {
+			continue
+		}
+		point, err := crypto.NewECPoint(btcec.S256(), pk.X, pk.Y)
+		if err != nil {
+			continue
+		}
+		pubKey, _, err := conversion.GetTssPubKey(point)
+		if err != nil {
+			return fmt.Errorf("fail to get the recovered pub key: %w", err)
+		}
+		if pubKey == expectedPubKey {
+			return nil
+		}
+	}
+	return ErrSignerMismatch
+}
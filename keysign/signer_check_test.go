@@ -0,0 +1,49 @@
+package keysign
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	bc "github.com/binance-chain/tss-lib/common"
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+)
+
+type SignerCheckTestSuite struct{}
+
+var _ = Suite(&SignerCheckTestSuite{})
+
+func getTestPubKey(c *C, sk *btcec.PrivateKey) string {
+	point, err := crypto.NewECPoint(btcec.S256(), sk.X, sk.Y)
+	c.Assert(err, IsNil)
+	pubKey, _, err := conversion.GetTssPubKey(point)
+	c.Assert(err, IsNil)
+	return pubKey
+}
+
+func (*SignerCheckTestSuite) TestVerifySigner(c *C) {
+	conversion.SetupBech32Prefix()
+	sk, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	other, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	msg := sha256.Sum256([]byte("hello"))
+	sig, err := sk.Sign(msg[:])
+	c.Assert(err, IsNil)
+	sigData := &bc.SignatureData{
+		R: sig.R.Bytes(),
+		S: sig.S.Bytes(),
+	}
+	c.Assert(VerifySigner(msg[:], sigData, getTestPubKey(c, sk)), IsNil)
+	err = VerifySigner(msg[:], sigData, getTestPubKey(c, other))
+	c.Assert(errors.Is(err, ErrSignerMismatch), Equals, true)
+	// the signature of another message does not recover the key either
+	otherMsg := sha256.Sum256([]byte("world"))
+	err = VerifySigner(otherMsg[:], sigData, getTestPubKey(c, sk))
+	c.Assert(errors.Is(err, ErrSignerMismatch), Equals, true)
+	c.Assert(VerifySigner(msg[:], nil, getTestPubKey(c, sk)), NotNil)
+	c.Assert(VerifySigner(msg[:], &bc.SignatureData{}, getTestPubKey(c, sk)), NotNil)
+}
@@ -23,6 +23,9 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/storage"
 )
 
+// ErrInsufficientSigners indicates the keysign party is too small to meet the threshold
+var ErrInsufficientSigners = errors.New("not enough signers")
+
 type TssKeySign struct {
 	logger          zerolog.Logger
 	tssCommonStruct *common.TssCommon
@@ -31,6 +34,7 @@ type TssKeySign struct {
 	commStopChan    chan struct{}
 	p2pComm         *p2p.Communication
 	stateManager    storage.LocalStateManager
+	deadline        time.Time
 }
 
 func NewTssKeySign(localP2PID string,
@@ -57,6 +61,11 @@ func (tKeySign *TssKeySign) GetTssCommonStruct() *common.TssCommon {
 	return tKeySign.tssCommonStruct
 }
 
+// SetDeadline set the time by which the keysign has to finish, the zero time means no deadline
+func (tKeySign *TssKeySign) SetDeadline(deadline time.Time) {
+	tKeySign.deadline = deadline
+}
+
 // signMessage
 func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage.KeygenLocalState, parties []string) (*bc.SignatureData, error) {
 	partiesID, localPartyID, err := conversion.GetParties(parties, localStateItem.LocalPartyKey)
@@ -72,6 +81,10 @@ func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage
 	if err != nil {
 		return nil, errors.New("fail to get threshold")
 	}
+	// tss-lib needs at least threshold+1 parties to sign, the committee may shrink after the filtering
+	if len(partiesID) <= threshold {
+		return nil, fmt.Errorf("%w, threshold=%d and signers=%d", ErrInsufficientSigners, threshold, len(partiesID))
+	}
 
 	tKeySign.logger.Debug().Msgf("local party: %+v", localPartyID)
 	ctx := btss.NewPeerContext(partiesID)
@@ -138,7 +151,8 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 	defer tKeySign.logger.Debug().Msg("key sign finished")
 	tKeySign.logger.Debug().Msg("start to read messages from local party")
 	tssConf := tKeySign.tssCommonStruct.GetConf()
-	blameMgr := tKeySign.tssCommonStruct.GetBlameMgr()
+	deadline, stopDeadline := common.DeadlineTimer(tKeySign.deadline)
+	defer stopDeadline()
 
 	for {
 		select {
@@ -147,56 +161,13 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 			return nil, errors.New("error channel closed fail to start local party")
 		case <-tKeySign.stopChan: // when TSS processor receive signal to quit
 			return nil, errors.New("received exit signal")
-		case <-time.After(tssConf.KeySignTimeout):
+		case <-time.After(tssConf.KeySignProtocolTimeout()):
 			// we bail out after KeySignTimeoutSeconds
-			tKeySign.logger.Error().Msgf("fail to sign message with %s", tssConf.KeySignTimeout.String())
-			lastMsg := blameMgr.GetLastMsg()
-			failReason := blameMgr.GetBlame().FailReason
-			if failReason == "" {
-				failReason = blame.TssTimeout
-			}
-			threshold, err := common.GetThreshold(len(tKeySign.tssCommonStruct.P2PPeers) + 1)
-			if err != nil {
-				tKeySign.logger.Error().Err(err).Msg("error in get the threshold for generate blame")
-			}
-			if !lastMsg.IsBroadcast() {
-				blameNodesUnicast, err := blameMgr.GetUnicastBlame(lastMsg.Type())
-				if err != nil {
-					tKeySign.logger.Error().Err(err).Msg("error in get unicast blame")
-				}
-				if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
-					blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
-				}
-			} else {
-				blameNodesUnicast, err := blameMgr.GetUnicastBlame(conversion.GetPreviousKeySignUicast(lastMsg.Type()))
-				if err != nil {
-					tKeySign.logger.Error().Err(err).Msg("error in get unicast blame")
-				}
-				if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
-					blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
-				}
-			}
-
-			blameNodesBroadcast, err := blameMgr.GetBroadcastBlame(lastMsg.Type())
-			if err != nil {
-				tKeySign.logger.Error().Err(err).Msg("error in get broadcast blame")
-			}
-			blameMgr.GetBlame().AddBlameNodes(blameNodesBroadcast...)
-
-			// if we cannot find the blame node, we check whether everyone send me the share
-			if len(blameMgr.GetBlame().BlameNodes) == 0 {
-				blameNodesMisingShare, isUnicast, err := blameMgr.TssMissingShareBlame(messages.TSSKEYSIGNROUNDS)
-				if err != nil {
-					tKeySign.logger.Error().Err(err).Msg("fail to get the node of missing share ")
-				}
-
-				if len(blameNodesMisingShare) > 0 && len(blameNodesMisingShare) <= threshold {
-					blameMgr.GetBlame().AddBlameNodes(blameNodesMisingShare...)
-					blameMgr.GetBlame().IsUnicast = isUnicast
-				}
-			}
-
-			return nil, blame.ErrTssTimeOut
+			tKeySign.logger.Error().Msgf("fail to sign message with %s", tssConf.KeySignProtocolTimeout().String())
+			return nil, tKeySign.processTimeout()
+		case <-deadline:
+			tKeySign.logger.Error().Msgf("keysign is not done within %s", tssConf.MaxCeremonyDuration)
+			return nil, tKeySign.processTimeout()
 		case msg := <-outCh:
 			tKeySign.logger.Debug().Msgf(">>>>>>>>>>key sign msg: %s", msg.String())
 			tKeySign.tssCommonStruct.GetBlameMgr().SetLastMsg(msg)
@@ -220,6 +191,62 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 	}
 }
 
+// processTimeout work out the nodes to blame when the keysign times out
+func (tKeySign *TssKeySign) processTimeout() error {
+	blameMgr := tKeySign.tssCommonStruct.GetBlameMgr()
+	lastMsg := blameMgr.GetLastMsg()
+	failReason := blameMgr.GetBlame().FailReason
+	if failReason == "" {
+		failReason = blame.TssTimeout
+	}
+	if lastMsg == nil {
+		tKeySign.logger.Error().Msg("fail to start the keysign, the last produced message of this node is none")
+		return errors.New("timeout before shared message is generated")
+	}
+	threshold, err := common.GetThreshold(len(tKeySign.tssCommonStruct.P2PPeers) + 1)
+	if err != nil {
+		tKeySign.logger.Error().Err(err).Msg("error in get the threshold for generate blame")
+	}
+	if !lastMsg.IsBroadcast() {
+		blameNodesUnicast, err := blameMgr.GetUnicastBlame(lastMsg.Type())
+		if err != nil {
+			tKeySign.logger.Error().Err(err).Msg("error in get unicast blame")
+		}
+		if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
+			blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
+		}
+	} else {
+		blameNodesUnicast, err := blameMgr.GetUnicastBlame(conversion.GetPreviousKeySignUicast(lastMsg.Type()))
+		if err != nil {
+			tKeySign.logger.Error().Err(err).Msg("error in get unicast blame")
+		}
+		if len(blameNodesUnicast) > 0 && len(blameNodesUnicast) <= threshold {
+			blameMgr.GetBlame().SetBlame(failReason, blameNodesUnicast, true)
+		}
+	}
+
+	blameNodesBroadcast, err := blameMgr.GetBroadcastBlame(lastMsg.Type())
+	if err != nil {
+		tKeySign.logger.Error().Err(err).Msg("error in get broadcast blame")
+	}
+	blameMgr.GetBlame().AddBlameNodes(blameNodesBroadcast...)
+
+	// if we cannot find the blame node, we check whether everyone send me the share
+	if len(blameMgr.GetBlame().BlameNodes) == 0 {
+		blameNodesMisingShare, isUnicast, err := blameMgr.TssMissingShareBlame(messages.TSSKEYSIGNROUNDS)
+		if err != nil {
+			tKeySign.logger.Error().Err(err).Msg("fail to get the node of missing share ")
+		}
+
+		if len(blameNodesMisingShare) > 0 && len(blameNodesMisingShare) <= threshold {
+			blameMgr.GetBlame().AddBlameNodes(blameNodesMisingShare...)
+			blameMgr.GetBlame().IsUnicast = isUnicast
+		}
+	}
+
+	return blame.ErrTssTimeOut
+}
+
 func (tKeySign *TssKeySign) WriteKeySignResult(w http.ResponseWriter, R, S string, status common.Status) {
 	signResp := Response{
 		R:      R,
@@ -23,6 +23,8 @@ const (
 	TSSControlMsg
 	// TSSTaskDone is the message of Tss process notification
 	TSSTaskDone
+	// TSSUnicastAck is the message we send back to acknowledge the unicast message
+	TSSUnicastAck
 	// Unknown is the message indicates the undefined message type
 	Unknown
 )
@@ -38,6 +40,8 @@ func (msgType THORChainTSSMessageType) String() string {
 		return "TSSKeyGenVerMsg"
 	case TSSKeySignVerMsg:
 		return "TSSKeySignVerMsg"
+	case TSSUnicastAck:
+		return "TSSUnicastAck"
 	default:
 		return "Unknown"
 	}
@@ -81,6 +85,13 @@ type TssControl struct {
 	ReqKey      string                  `json:"request_key"`
 	RequestType THORChainTSSMessageType `json:"request_type"`
 	Msg         *WireMessage            `json:"message_body"`
+	// Sig is the signature of the requester over the request, so the responder knows who is asking
+	Sig []byte `json:"signature,omitempty"`
+}
+
+// UnicastAck acknowledge the receipt of the unicast message with the given cache key
+type UnicastAck struct {
+	Key string `json:"key"`
 }
 
 type TssTaskNotifier struct {
@@ -0,0 +1,85 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+
+	maddr "github.com/multiformats/go-multiaddr"
+)
+
+// AddrFilter decides which of the local addresses we announce to the peers
+type AddrFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(cidrs))
+	for _, el := range cidrs {
+		_, ipNet, err := net.ParseCIDR(el)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse CIDR(%s): %w", el, err)
+		}
+		result = append(result, ipNet)
+	}
+	return result, nil
+}
+
+// NewAddrFilter create a new instance of AddrFilter, if the allow list is empty, we announce all the addresses
+// that are not in the deny list
+func NewAddrFilter(allow, deny []string) (*AddrFilter, error) {
+	allowNets, err := parseCIDRs(allow)
+	if err != nil {
+		return nil, err
+	}
+	denyNets, err := parseCIDRs(deny)
+	if err != nil {
+		return nil, err
+	}
+	return &AddrFilter{
+		allow: allowNets,
+		deny:  denyNets,
+	}, nil
+}
+
+func addrToIP(addr maddr.Multiaddr) net.IP {
+	if value, err := addr.ValueForProtocol(maddr.P_IP4); err == nil {
+		return net.ParseIP(value)
+	}
+	if value, err := addr.ValueForProtocol(maddr.P_IP6); err == nil {
+		return net.ParseIP(value)
+	}
+	return nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, el := range nets {
+		if el.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed check whether we should announce the given address, the addresses without an IP are always allowed
+func (af *AddrFilter) Allowed(addr maddr.Multiaddr) bool {
+	ip := addrToIP(addr)
+	if ip == nil {
+		return true
+	}
+	if containsIP(af.deny, ip) {
+		return false
+	}
+	return len(af.allow) == 0 || containsIP(af.allow, ip)
+}
+
+// Filter return the addresses we should announce
+func (af *AddrFilter) Filter(addrs []maddr.Multiaddr) []maddr.Multiaddr {
+	result := make([]maddr.Multiaddr, 0, len(addrs))
+	for _, el := range addrs {
+		if af.Allowed(el) {
+			result = append(result, el)
+		}
+	}
+	return result
+}
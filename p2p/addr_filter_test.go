@@ -0,0 +1,35 @@
+package p2p
+
+import (
+	maddr "github.com/multiformats/go-multiaddr"
+	. "gopkg.in/check.v1"
+)
+
+type AddrFilterTestSuite struct{}
+
+var _ = Suite(&AddrFilterTestSuite{})
+
+func (AddrFilterTestSuite) TestAddrFilter(c *C) {
+	_, err := NewAddrFilter([]string{"whatever"}, nil)
+	c.Assert(err, NotNil)
+	_, err = NewAddrFilter(nil, []string{"10.0.0.1"})
+	c.Assert(err, NotNil)
+
+	public := maddr.StringCast("/ip4/11.22.33.44/tcp/6668")
+	private := maddr.StringCast("/ip4/10.1.2.3/tcp/6668")
+	docker := maddr.StringCast("/ip4/172.17.0.2/tcp/6668")
+	dns := maddr.StringCast("/dns4/example.com/tcp/6668")
+	addrs := []maddr.Multiaddr{public, private, docker, dns}
+
+	filter, err := NewAddrFilter(nil, []string{"10.0.0.0/8", "172.16.0.0/12"})
+	c.Assert(err, IsNil)
+	c.Assert(filter.Filter(addrs), DeepEquals, []maddr.Multiaddr{public, dns})
+
+	filter, err = NewAddrFilter([]string{"10.0.0.0/8"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(filter.Filter(addrs), DeepEquals, []maddr.Multiaddr{private, dns})
+
+	filter, err = NewAddrFilter([]string{"10.0.0.0/8"}, []string{"10.1.0.0/16"})
+	c.Assert(err, IsNil)
+	c.Assert(filter.Allowed(private), Equals, false)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p"
@@ -32,6 +33,8 @@ var TSSProtocolID protocol.ID = "/p2p/tss"
 const (
 	// TimeoutConnecting maximum time for wait for peers to connect
 	TimeoutConnecting = time.Minute * 1
+	// deliverTimeout maximum time we wait for the ceremony queue to have room for an inbound message
+	deliverTimeout = time.Second * 30
 )
 
 // Message that get transfer across the wire
@@ -48,17 +51,30 @@ type Communication struct {
 	listenAddr       maddr.Multiaddr
 	host             host.Host
 	wg               *sync.WaitGroup
-	stopChan         chan struct{} // channel to indicate whether we should stop
+	ctx              context.Context // the context is cancelled when the communication stops
+	cancel           context.CancelFunc
 	subscribers      map[messages.THORChainTSSMessageType]*MessageIDSubscriber
 	subscriberLocker *sync.Mutex
 	streamCount      int64
 	BroadcastMsgChan chan *messages.BroadcastMsgChan
 	externalAddr     maddr.Multiaddr
 	streamMgr        *StreamMgr
+	droppedMsgs      uint64
+	deliverTimeout   time.Duration
+	pauseCond        *sync.Cond
+	paused           bool
+	addrFilter       *AddrFilter
+	maxPayload       uint32
 }
 
 // NewCommunication create a new instance of Communication
 func NewCommunication(rendezvous string, bootstrapPeers []maddr.Multiaddr, port int, externalIP string) (*Communication, error) {
+	return NewCommunicationWithContext(context.Background(), rendezvous, bootstrapPeers, port, externalIP)
+}
+
+// NewCommunicationWithContext create a new instance of Communication, all the goroutines and the network calls of
+// the communication quit once the given context is cancelled or the communication stops
+func NewCommunicationWithContext(parent context.Context, rendezvous string, bootstrapPeers []maddr.Multiaddr, port int, externalIP string) (*Communication, error) {
 	addr, err := maddr.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", port))
 	if err != nil {
 		return nil, fmt.Errorf("fail to create listen addr: %w", err)
@@ -70,22 +86,90 @@ func NewCommunication(rendezvous string, bootstrapPeers []maddr.Multiaddr, port
 			return nil, fmt.Errorf("fail to create listen with given external IP: %w", err)
 		}
 	}
+	ctx, cancel := context.WithCancel(parent)
 	return &Communication{
 		rendezvous:       rendezvous,
 		bootstrapPeers:   bootstrapPeers,
 		logger:           log.With().Str("module", "communication").Logger(),
 		listenAddr:       addr,
 		wg:               &sync.WaitGroup{},
-		stopChan:         make(chan struct{}),
+		ctx:              ctx,
+		cancel:           cancel,
 		subscribers:      make(map[messages.THORChainTSSMessageType]*MessageIDSubscriber),
 		subscriberLocker: &sync.Mutex{},
 		streamCount:      0,
 		BroadcastMsgChan: make(chan *messages.BroadcastMsgChan, 1024),
 		externalAddr:     externalAddr,
 		streamMgr:        NewStreamMgr(),
+		deliverTimeout:   deliverTimeout,
+		maxPayload:       MaxPayload,
+		pauseCond:        sync.NewCond(&sync.Mutex{}),
 	}, nil
 }
 
+// SetAddrFilter set the filter of the addresses we announce, it should be called before Start, it has no effect
+// if an external IP is given
+func (c *Communication) SetAddrFilter(filter *AddrFilter) {
+	c.addrFilter = filter
+}
+
+// SetMaxPayload set the max payload of the tss messages we accept, zero restores MaxPayload
+func (c *Communication) SetMaxPayload(size uint32) {
+	if size == 0 {
+		size = MaxPayload
+	}
+	c.maxPayload = size
+}
+
+// Pause hold the inbound and outbound messages until Resume is called, the p2p connections are kept open and the
+// held messages are delivered once we resume. The ceremony timeouts are still running while we are paused.
+func (c *Communication) Pause() {
+	c.pauseCond.L.Lock()
+	defer c.pauseCond.L.Unlock()
+	c.paused = true
+	c.logger.Info().Msg("message processing paused")
+}
+
+// Resume deliver the held messages and carry on processing
+func (c *Communication) Resume() {
+	c.pauseCond.L.Lock()
+	defer c.pauseCond.L.Unlock()
+	c.paused = false
+	c.pauseCond.Broadcast()
+	c.logger.Info().Msg("message processing resumed")
+}
+
+// IsPaused return whether the message processing is paused
+func (c *Communication) IsPaused() bool {
+	c.pauseCond.L.Lock()
+	defer c.pauseCond.L.Unlock()
+	return c.paused
+}
+
+// waitIfPaused block until the communication is resumed or stopped
+func (c *Communication) waitIfPaused() {
+	c.pauseCond.L.Lock()
+	defer c.pauseCond.L.Unlock()
+	for c.paused {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+		c.pauseCond.Wait()
+	}
+}
+
+// GetDroppedMessages return how many inbound messages we dropped as the queue of the ceremony stayed full
+func (c *Communication) GetDroppedMessages() uint64 {
+	return atomic.LoadUint64(&c.droppedMsgs)
+}
+
+// Context return the context of the communication, it is cancelled once the communication stops
+func (c *Communication) Context() context.Context {
+	return c.ctx
+}
+
 // GetHost return the host
 func (c *Communication) GetHost() host.Host {
 	return c.host
@@ -150,10 +234,10 @@ func (c *Communication) readFromStream(stream network.Stream) {
 	c.logger.Debug().Msgf("reading from stream of peer: %s", peerID)
 
 	select {
-	case <-c.stopChan:
+	case <-c.ctx.Done():
 		return
 	default:
-		dataBuf, err := ReadStreamWithBuffer(stream)
+		dataBuf, err := ReadStreamWithBuffer(stream, c.maxPayload)
 		if err != nil {
 			c.logger.Error().Err(err).Msgf("fail to read from stream,peerID: %s", peerID)
 			c.streamMgr.AddStream("UNKNOWN", stream)
@@ -167,18 +251,36 @@ func (c *Communication) readFromStream(stream network.Stream) {
 		}
 		c.logger.Debug().Msgf(">>>>>>>[%s] %s", wrappedMsg.MessageType, string(wrappedMsg.Payload))
 		c.streamMgr.AddStream(wrappedMsg.MsgID, stream)
+		c.waitIfPaused()
 		channel := c.getSubscriber(wrappedMsg.MessageType, wrappedMsg.MsgID)
 		if nil == channel {
 			c.logger.Debug().Msgf("no MsgID %s found for this message", wrappedMsg.MsgID)
 			c.logger.Debug().Msgf("no MsgID %s found for this message", wrappedMsg.MessageType)
 			return
 		}
-		channel <- &Message{
+		c.deliver(channel, &Message{
 			PeerID:  stream.Conn().RemotePeer(),
 			Payload: dataBuf,
-		}
+		}, wrappedMsg.MsgID)
+	}
+}
 
+// deliver block until the ceremony takes the message, the message is dropped only if the queue stays full for
+// deliverTimeout or the communication stops
+func (c *Communication) deliver(channel chan *Message, msg *Message, msgID string) bool {
+	timer := time.NewTimer(c.deliverTimeout)
+	defer timer.Stop()
+	select {
+	case channel <- msg:
+		return true
+	case <-timer.C:
+		atomic.AddUint64(&c.droppedMsgs, 1)
+		c.logger.Warn().Msgf("message queue of MsgID %s stays full, drop the message from peer %s", msgID, msg.PeerID)
+	case <-c.ctx.Done():
+		atomic.AddUint64(&c.droppedMsgs, 1)
+		c.logger.Warn().Msgf("communication stops, drop the message of MsgID %s from peer %s", msgID, msg.PeerID)
 	}
+	return false
 }
 
 func (c *Communication) handleStream(stream network.Stream) {
@@ -193,7 +295,7 @@ func (c *Communication) bootStrapConnectivityCheck() error {
 		c.logger.Error().Msg("we do not have the bootstrap node set, quit the connectivity check")
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	ctx, cancel := context.WithTimeout(c.ctx, time.Second*2)
 	defer cancel()
 
 	for _, el := range c.bootstrapPeers {
@@ -222,7 +324,8 @@ func (c *Communication) bootStrapConnectivityCheck() error {
 }
 
 func (c *Communication) startChannel(privKeyBytes []byte) error {
-	ctx := context.Background()
+	// the DHT and the advertisement keep running in the background until the context is cancelled
+	ctx := c.ctx
 	p2pPriKey, err := crypto.UnmarshalSecp256k1PrivateKey(privKeyBytes)
 	if err != nil {
 		c.logger.Error().Msgf("error is %f", err)
@@ -233,6 +336,9 @@ func (c *Communication) startChannel(privKeyBytes []byte) error {
 		if c.externalAddr != nil {
 			return []maddr.Multiaddr{c.externalAddr}
 		}
+		if c.addrFilter != nil {
+			return c.addrFilter.Filter(addrs)
+		}
 		return addrs
 	}
 
@@ -267,7 +373,11 @@ func (c *Communication) startChannel(privKeyBytes []byte) error {
 			break
 		}
 		c.logger.Error().Msg("cannot connect to any bootstrap node, retry in 5 seconds")
-		time.Sleep(time.Second * 5)
+		select {
+		case <-time.After(time.Second * 5):
+		case <-ctx.Done():
+			return fmt.Errorf("fail to connect to bootstrap peer: %w", ctx.Err())
+		}
 	}
 	if connectionErr != nil {
 		return fmt.Errorf("fail to connect to bootstrap peer: %w", connectionErr)
@@ -293,7 +403,7 @@ func (c *Communication) connectToOnePeer(pID peer.ID) (network.Stream, error) {
 		return nil, nil
 	}
 	c.logger.Debug().Msgf("connect to peer : %s", pID.String())
-	ctx, cancel := context.WithTimeout(context.Background(), TimeoutConnecting)
+	ctx, cancel := context.WithTimeout(c.ctx, TimeoutConnecting)
 	defer cancel()
 	stream, err := c.host.NewStream(ctx, pID, TSSProtocolID)
 	if err != nil {
@@ -319,7 +429,7 @@ func (c *Communication) connectToBootstrapPeers() error {
 		wg.Add(1)
 		go func(connRet chan bool) {
 			defer wg.Done()
-			ctx, cancel := context.WithTimeout(context.Background(), TimeoutConnecting)
+			ctx, cancel := context.WithTimeout(c.ctx, TimeoutConnecting)
 			defer cancel()
 			if err := c.host.Connect(ctx, *pi); err != nil {
 				c.logger.Error().Err(err).Msgf("fail to connect to %s", pi.String())
@@ -356,7 +466,13 @@ func (c *Communication) Stop() error {
 		c.logger.Err(err).Msg("fail to close host network")
 	}
 
-	close(c.stopChan)
+	c.cancel()
+	// wake up the goroutines that wait for resume, so that they can quit, we hold the lock so that a goroutine
+	// cannot miss the wake up between checking the context and waiting
+	c.pauseCond.L.Lock()
+	c.paused = false
+	c.pauseCond.Broadcast()
+	c.pauseCond.L.Unlock()
 	c.wg.Wait()
 	return nil
 }
@@ -414,10 +530,11 @@ func (c *Communication) ProcessBroadcast() {
 				c.logger.Error().Err(err).Msg("fail to marshal a wrapped message to json bytes")
 				continue
 			}
+			c.waitIfPaused()
 			c.logger.Debug().Msgf("broadcast message %s to %+v", msg.WrappedMessage, msg.PeersID)
 			c.Broadcast(msg.PeersID, wrappedMsgBytes, msg.WrappedMessage.MsgID)
 
-		case <-c.stopChan:
+		case <-c.ctx.Done():
 			return
 		}
 	}
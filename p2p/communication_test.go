@@ -3,6 +3,7 @@ package p2p
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	maddr "github.com/multiformats/go-multiaddr"
@@ -29,6 +30,81 @@ func (CommunicationTestSuite) TestBasicCommunication(c *C) {
 	comm.CancelSubscribe(messages.TSSKeySignMsg, "asdsdf")
 }
 
+func (CommunicationTestSuite) TestPauseResume(c *C) {
+	comm, err := NewCommunication("rendezvous", nil, 6668, "")
+	c.Assert(err, IsNil)
+	comm.Pause()
+	c.Assert(comm.IsPaused(), Equals, true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		comm.waitIfPaused()
+	}()
+	select {
+	case <-done:
+		c.Fatal("should wait while paused")
+	case <-time.After(time.Millisecond * 200):
+	}
+	comm.Resume()
+	c.Assert(comm.IsPaused(), Equals, false)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		c.Fatal("should carry on after resume")
+	}
+}
+
+func (CommunicationTestSuite) TestStopWhilePaused(c *C) {
+	sk, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	c.Assert(err, IsNil)
+	skRaw, err := sk.Raw()
+	c.Assert(err, IsNil)
+	comm, err := NewCommunication("rendezvous", nil, 2224, "")
+	c.Assert(err, IsNil)
+	c.Assert(comm.Start(skRaw), IsNil)
+	comm.Pause()
+	for i := 0; i < 10; i++ {
+		comm.wg.Add(1)
+		go func() {
+			defer comm.wg.Done()
+			comm.waitIfPaused()
+		}()
+	}
+	stopped := make(chan error)
+	go func() {
+		stopped <- comm.Stop()
+	}()
+	select {
+	case err := <-stopped:
+		c.Assert(err, IsNil)
+	case <-time.After(time.Second * 5):
+		c.Fatal("stop should wake up the goroutines that wait for resume")
+	}
+	c.Assert(comm.IsPaused(), Equals, false)
+}
+
+func (CommunicationTestSuite) TestDeliver(c *C) {
+	comm, err := NewCommunication("rendezvous", nil, 6668, "")
+	c.Assert(err, IsNil)
+	comm.deliverTimeout = time.Millisecond * 200
+	channel := make(chan *Message, 1)
+	c.Assert(comm.deliver(channel, &Message{}, "hello"), Equals, true)
+	// the queue is full, the message is delivered once the ceremony takes the queued one
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		<-channel
+	}()
+	c.Assert(comm.deliver(channel, &Message{}, "hello"), Equals, true)
+	c.Assert(comm.GetDroppedMessages(), Equals, uint64(0))
+	// nobody takes the message before the timeout
+	c.Assert(comm.deliver(channel, &Message{}, "hello"), Equals, false)
+	c.Assert(comm.GetDroppedMessages(), Equals, uint64(1))
+	comm.cancel()
+	comm.deliverTimeout = time.Minute
+	c.Assert(comm.deliver(channel, &Message{}, "hello"), Equals, false)
+	c.Assert(comm.GetDroppedMessages(), Equals, uint64(2))
+}
+
 func checkExist(a []maddr.Multiaddr, b string) bool {
 	for _, el := range a {
 		if el.String() == b {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
@@ -17,42 +18,87 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/messages"
 )
 
+// DefaultJoinPartyConcurrency is how many join party requests we send at the same time if it is not configured
+const DefaultJoinPartyConcurrency = 10
+
+// ErrJoinPartyCancelled is returned when the join party is cancelled before all the peers join
+var ErrJoinPartyCancelled = errors.New("fail to join party, cancelled")
+
+// ErrJoinPartyStopped is returned when the party coordinator stops before all the peers join
+var ErrJoinPartyStopped = errors.New("fail to join party, party coordinator stopped")
+
 var errJoinPartyTimeout = errors.New("fail to join party, timeout")
 
 type PartyCoordinator struct {
 	logger             zerolog.Logger
 	host               host.Host
-	stopChan           chan struct{}
+	ctx                context.Context // the context is cancelled when the party coordinator stops
+	cancel             context.CancelFunc
 	timeout            time.Duration
 	peersGroup         map[string]*PeerStatus
 	joinPartyGroupLock *sync.Mutex
 	streamMgr          *StreamMgr
+	sendConcurrency    int
+	inFlightSends      int64
+	maxPayload         uint32
 }
 
 // NewPartyCoordinator create a new instance of PartyCoordinator
 func NewPartyCoordinator(host host.Host, timeout time.Duration) *PartyCoordinator {
+	return NewPartyCoordinatorWithContext(context.Background(), host, timeout)
+}
+
+// NewPartyCoordinatorWithContext create a new instance of PartyCoordinator, the join parties in progress give up
+// once the given context is cancelled or the party coordinator stops
+func NewPartyCoordinatorWithContext(parent context.Context, host host.Host, timeout time.Duration) *PartyCoordinator {
 	// if no timeout is given, default to 10 seconds
 	if timeout.Nanoseconds() == 0 {
 		timeout = 10 * time.Second
 	}
+	ctx, cancel := context.WithCancel(parent)
 	pc := &PartyCoordinator{
 		logger:             log.With().Str("module", "party_coordinator").Logger(),
 		host:               host,
-		stopChan:           make(chan struct{}),
+		ctx:                ctx,
+		cancel:             cancel,
 		timeout:            timeout,
 		peersGroup:         make(map[string]*PeerStatus),
 		joinPartyGroupLock: &sync.Mutex{},
 		streamMgr:          NewStreamMgr(),
+		sendConcurrency:    DefaultJoinPartyConcurrency,
+		maxPayload:         DefaultJoinPartyMaxPayload,
 	}
 	host.SetStreamHandler(joinPartyProtocol, pc.HandleStream)
 	return pc
 }
 
+// SetSendConcurrency set how many join party requests we send at the same time, a non-positive value restores
+// the default
+func (pc *PartyCoordinator) SetSendConcurrency(concurrency int) {
+	if concurrency <= 0 {
+		concurrency = DefaultJoinPartyConcurrency
+	}
+	pc.sendConcurrency = concurrency
+}
+
+// SetMaxPayload set the max payload of the join party requests we accept, zero restores the default
+func (pc *PartyCoordinator) SetMaxPayload(size uint32) {
+	if size == 0 {
+		size = DefaultJoinPartyMaxPayload
+	}
+	pc.maxPayload = size
+}
+
+// GetInFlightSends return how many join party requests are being sent at the moment
+func (pc *PartyCoordinator) GetInFlightSends() int64 {
+	return atomic.LoadInt64(&pc.inFlightSends)
+}
+
 // Stop the PartyCoordinator rune
 func (pc *PartyCoordinator) Stop() {
 	defer pc.logger.Info().Msg("stop party coordinator")
 	pc.host.RemoveStreamHandler(joinPartyProtocol)
-	close(pc.stopChan)
+	pc.cancel()
 }
 
 // HandleStream handle party coordinate stream
@@ -60,7 +106,7 @@ func (pc *PartyCoordinator) HandleStream(stream network.Stream) {
 	remotePeer := stream.Conn().RemotePeer()
 	logger := pc.logger.With().Str("remote peer", remotePeer.String()).Logger()
 	logger.Debug().Msg("reading from join party request")
-	payload, err := ReadStreamWithBuffer(stream)
+	payload, err := ReadStreamWithBuffer(stream, pc.maxPayload)
 	if err != nil {
 		logger.Err(err).Msgf("fail to read payload from stream")
 		pc.streamMgr.AddStream("UNKNOWN", stream)
@@ -80,6 +126,11 @@ func (pc *PartyCoordinator) HandleStream(stream network.Stream) {
 		pc.logger.Info().Msg("this party is not ready")
 		return
 	}
+	if err := peerGroup.validateThreshold(msg.Threshold); err != nil {
+		// we do not mark the peer as online, so it gets blamed for failing to join the party
+		logger.Error().Err(err).Msgf("reject the join party request with invalid threshold, blame peer %s", remotePeer)
+		return
+	}
 	newFound, err := peerGroup.updatePeer(remotePeer)
 	if err != nil {
 		pc.logger.Error().Err(err).Msg("receive msg from unknown peer")
@@ -96,15 +147,19 @@ func (pc *PartyCoordinator) removePeerGroup(messageID string) {
 	delete(pc.peersGroup, messageID)
 }
 
-func (pc *PartyCoordinator) createJoinPartyGroups(messageID string, peers []string) (*PeerStatus, error) {
+func (pc *PartyCoordinator) createJoinPartyGroups(messageID string, peers []string, threshold int32) (*PeerStatus, error) {
 	pIDs, err := pc.getPeerIDs(peers)
 	if err != nil {
 		pc.logger.Error().Err(err).Msg("fail to parse peer id")
 		return nil, err
 	}
+	if err := checkThreshold(threshold, len(pIDs)); err != nil {
+		return nil, err
+	}
 	pc.joinPartyGroupLock.Lock()
 	defer pc.joinPartyGroupLock.Unlock()
 	peerStatus := NewPeerStatus(pIDs, pc.host.ID())
+	peerStatus.threshold = threshold
 	pc.peersGroup[messageID] = peerStatus
 	return peerStatus, nil
 }
@@ -123,10 +178,18 @@ func (pc *PartyCoordinator) getPeerIDs(ids []string) ([]peer.ID, error) {
 
 func (pc *PartyCoordinator) sendRequestToAll(msg *messages.JoinPartyRequest, peers []peer.ID) {
 	var wg sync.WaitGroup
-	wg.Add(len(peers))
+	// we only allow sendConcurrency requests in flight, so a large committee does not spike goroutines and streams
+	slots := make(chan struct{}, pc.sendConcurrency)
 	for _, el := range peers {
+		slots <- struct{}{}
+		wg.Add(1)
 		go func(peer peer.ID) {
-			defer wg.Done()
+			atomic.AddInt64(&pc.inFlightSends, 1)
+			defer func() {
+				atomic.AddInt64(&pc.inFlightSends, -1)
+				<-slots
+				wg.Done()
+			}()
 			if err := pc.sendRequestToPeer(msg, peer); err != nil {
 				pc.logger.Error().Err(err).Msg("error in send the join party request to peer")
 			}
@@ -140,7 +203,7 @@ func (pc *PartyCoordinator) sendRequestToPeer(msg *messages.JoinPartyRequest, re
 	if err != nil {
 		return fmt.Errorf("fail to marshal msg to bytes: %w", err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+	ctx, cancel := context.WithTimeout(pc.ctx, time.Second*4)
 	defer cancel()
 	var stream network.Stream
 	var streamError error
@@ -182,7 +245,19 @@ func (pc *PartyCoordinator) sendRequestToPeer(msg *messages.JoinPartyRequest, re
 
 // JoinPartyWithRetry this method provide the functionality to join party with retry and back off
 func (pc *PartyCoordinator) JoinPartyWithRetry(msg *messages.JoinPartyRequest, peers []string) ([]peer.ID, error) {
-	peerGroup, err := pc.createJoinPartyGroups(msg.ID, peers)
+	return pc.JoinPartyWithDeadline(msg, peers, time.Time{})
+}
+
+// JoinPartyWithDeadline join the party like JoinPartyWithRetry, but also gives up once the given deadline is
+// reached, the zero time means no deadline
+func (pc *PartyCoordinator) JoinPartyWithDeadline(msg *messages.JoinPartyRequest, peers []string, deadline time.Time) ([]peer.ID, error) {
+	var deadlineCh <-chan time.Time
+	if !deadline.IsZero() {
+		deadlineTimer := time.NewTimer(time.Until(deadline))
+		defer deadlineTimer.Stop()
+		deadlineCh = deadlineTimer.C
+	}
+	peerGroup, err := pc.createJoinPartyGroups(msg.ID, peers, msg.Threshold)
 	if err != nil {
 		pc.logger.Error().Err(err).Msg("fail to create the join party group")
 		return nil, err
@@ -198,10 +273,18 @@ func (pc *PartyCoordinator) JoinPartyWithRetry(msg *messages.JoinPartyRequest, p
 			select {
 			case <-done:
 				return
+			case <-pc.ctx.Done():
+				return
 			default:
 				pc.sendRequestToAll(msg, offline)
 			}
-			time.Sleep(time.Second)
+			select {
+			case <-done:
+				return
+			case <-pc.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
 		}
 	}()
 	// this is the total time TSS will wait for the party to form
@@ -216,25 +299,73 @@ func (pc *PartyCoordinator) JoinPartyWithRetry(msg *messages.JoinPartyRequest, p
 					close(done)
 					return
 				}
+			case <-peerGroup.cancel:
+				pc.logger.Info().Msgf("join party of %s is cancelled", msg.ID)
+				close(done)
+				return
 			case <-time.After(pc.timeout):
 				// timeout
 				close(done)
 				return
+			case <-deadlineCh:
+				pc.logger.Info().Msgf("join party of %s reaches the ceremony deadline", msg.ID)
+				close(done)
+				return
+			case <-pc.ctx.Done():
+				close(done)
+				return
 			}
 		}
 	}()
 
 	wg.Wait()
 	onlinePeers, _ := peerGroup.getPeersStatus()
-	pc.sendRequestToAll(msg, onlinePeers)
+	// if the party is cancelled we do not tell the online peers that we are ready
+	cancelled := peerGroup.isCancelled() && len(onlinePeers)+1 != len(peers)
+	if !cancelled {
+		pc.sendRequestToAll(msg, onlinePeers)
+	}
 	// we always set ourselves as online
 	onlinePeers = append(onlinePeers, pc.host.ID())
 	if len(onlinePeers) == len(peers) {
 		return onlinePeers, nil
 	}
+	if cancelled {
+		return onlinePeers, ErrJoinPartyCancelled
+	}
+	if pc.ctx.Err() != nil {
+		return onlinePeers, ErrJoinPartyStopped
+	}
 	return onlinePeers, errJoinPartyTimeout
 }
 
+// CancelJoinParty abort the join party of the given message id that is in progress, it returns false if we are
+// not waiting for that party
+func (pc *PartyCoordinator) CancelJoinParty(msgID string) bool {
+	pc.joinPartyGroupLock.Lock()
+	peerGroup, ok := pc.peersGroup[msgID]
+	pc.joinPartyGroupLock.Unlock()
+	if !ok {
+		return false
+	}
+	peerGroup.cancelJoin()
+	return true
+}
+
+// EjectPeer remove the peer from all the join parties in progress, the peer is treated as offline so it gets blamed
+// for failing to join, it returns the message ids of the parties the peer is ejected from
+func (pc *PartyCoordinator) EjectPeer(peerID peer.ID) []string {
+	pc.joinPartyGroupLock.Lock()
+	defer pc.joinPartyGroupLock.Unlock()
+	var msgIDs []string
+	for msgID, peerGroup := range pc.peersGroup {
+		if peerGroup.ejectPeer(peerID) {
+			msgIDs = append(msgIDs, msgID)
+		}
+	}
+	return msgIDs
+}
+
 func (pc *PartyCoordinator) ReleaseStream(msgID string) {
 	pc.streamMgr.ReleaseStream(msgID)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 	tnet "github.com/libp2p/go-libp2p-testing/net"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/stretchr/testify/assert"
@@ -60,7 +61,8 @@ func TestNewPartyCoordinator(t *testing.T) {
 
 	msgID := conversion.RandStringBytesMask(64)
 	joinPartyReq := messages.JoinPartyRequest{
-		ID: msgID,
+		ID:        msgID,
+		Threshold: 2,
 	}
 	wg := sync.WaitGroup{}
 
@@ -108,7 +110,8 @@ func TestNewPartyCoordinatorTimeOut(t *testing.T) {
 	msgID := conversion.RandStringBytesMask(64)
 
 	joinPartyReq := messages.JoinPartyRequest{
-		ID: msgID,
+		ID:        msgID,
+		Threshold: 2,
 	}
 	wg := sync.WaitGroup{}
 
@@ -132,6 +135,85 @@ func TestNewPartyCoordinatorTimeOut(t *testing.T) {
 	wg.Wait()
 }
 
+func TestCancelJoinParty(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 4)
+	var pcs []*PartyCoordinator
+	var peers []string
+	for _, el := range hosts {
+		pcs = append(pcs, NewPartyCoordinator(el, time.Second*30))
+		peers = append(peers, el.ID().String())
+	}
+	defer func() {
+		for _, el := range pcs {
+			el.Stop()
+		}
+	}()
+
+	msgID := conversion.RandStringBytesMask(64)
+	assert.False(t, pcs[0].CancelJoinParty(msgID))
+	joinPartyReq := messages.JoinPartyRequest{
+		ID:        msgID,
+		Threshold: 2,
+	}
+	wg := sync.WaitGroup{}
+	for _, el := range pcs[:2] {
+		wg.Add(1)
+		go func(coordinator *PartyCoordinator) {
+			defer wg.Done()
+			onlinePeers, err := coordinator.JoinPartyWithRetry(&joinPartyReq, peers)
+			assert.Equal(t, ErrJoinPartyCancelled, err)
+			assert.Len(t, onlinePeers, 2)
+		}(el)
+	}
+	// give the two nodes some time to find each other before we abort the party
+	time.Sleep(time.Second * 3)
+	start := time.Now()
+	for _, el := range pcs[:2] {
+		assert.True(t, el.CancelJoinParty(msgID))
+	}
+	wg.Wait()
+	assert.True(t, time.Since(start) < time.Second*10)
+	assert.False(t, pcs[0].CancelJoinParty(msgID))
+}
+
+func TestStopJoinParty(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	var pcs []*PartyCoordinator
+	var peers []string
+	for _, el := range hosts {
+		pcs = append(pcs, NewPartyCoordinatorWithContext(ctx, el, time.Second*30))
+		peers = append(peers, el.ID().String())
+	}
+	defer func() {
+		for _, el := range pcs {
+			el.Stop()
+		}
+	}()
+
+	joinPartyReq := messages.JoinPartyRequest{
+		ID:        conversion.RandStringBytesMask(64),
+		Threshold: 1,
+	}
+	wg := sync.WaitGroup{}
+	for _, el := range pcs[:2] {
+		wg.Add(1)
+		go func(coordinator *PartyCoordinator) {
+			defer wg.Done()
+			_, err := coordinator.JoinPartyWithRetry(&joinPartyReq, peers)
+			assert.Equal(t, ErrJoinPartyStopped, err)
+		}(el)
+	}
+	time.Sleep(time.Second * 2)
+	start := time.Now()
+	// cancelling the parent context stops all the join parties in progress
+	cancel()
+	wg.Wait()
+	assert.True(t, time.Since(start) < time.Second*5)
+}
+
 func TestGetPeerIDs(t *testing.T) {
 	ApplyDeadline = false
 	id1 := tnet.RandIdentityOrFatal(t)
@@ -161,3 +243,107 @@ func TestGetPeerIDs(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Len(t, r2, 0)
 }
+
+func TestSendConcurrency(t *testing.T) {
+	hosts := setupHosts(t, 2)
+	pc := NewPartyCoordinator(hosts[0], time.Second)
+	defer pc.Stop()
+	assert.Equal(t, DefaultJoinPartyConcurrency, pc.sendConcurrency)
+	pc.SetSendConcurrency(1)
+	assert.Equal(t, 1, pc.sendConcurrency)
+	pc.SetSendConcurrency(-1)
+	assert.Equal(t, DefaultJoinPartyConcurrency, pc.sendConcurrency)
+
+	pc.SetSendConcurrency(2)
+	peers := []peer.ID{hosts[1].ID()}
+	for i := 0; i < 4; i++ {
+		peers = append(peers, conversion.GetRandomPeerID())
+	}
+	msg := &messages.JoinPartyRequest{
+		ID:        conversion.RandStringBytesMask(64),
+		Threshold: 1,
+	}
+	pc.sendRequestToAll(msg, peers)
+	assert.Equal(t, int64(0), pc.GetInFlightSends())
+}
+
+// the peer that joins the party is identified by the authenticated remote peer of the stream, a host outside the
+// committee cannot make a committee member look online
+func TestJoinPartyFromOutsider(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 4)
+	var pcs []*PartyCoordinator
+	for _, el := range hosts {
+		pcs = append(pcs, NewPartyCoordinator(el, time.Second*2))
+	}
+	defer func() {
+		for _, el := range pcs {
+			el.Stop()
+		}
+	}()
+	committee := []string{hosts[0].ID().String(), hosts[1].ID().String(), hosts[2].ID().String()}
+	outsiderCommittee := []string{hosts[0].ID().String(), hosts[1].ID().String(), hosts[3].ID().String()}
+	msgID := conversion.RandStringBytesMask(64)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		joinPartyReq := messages.JoinPartyRequest{
+			ID:        msgID,
+			Threshold: 1,
+		}
+		_, err := pcs[3].JoinPartyWithRetry(&joinPartyReq, outsiderCommittee)
+		assert.NotNil(t, err)
+	}()
+	for _, el := range pcs[:2] {
+		wg.Add(1)
+		go func(coordinator *PartyCoordinator) {
+			defer wg.Done()
+			joinPartyReq := messages.JoinPartyRequest{
+				ID:        msgID,
+				Threshold: 1,
+			}
+			onlinePeers, err := coordinator.JoinPartyWithRetry(&joinPartyReq, committee)
+			assert.Equal(t, errJoinPartyTimeout, err)
+			assert.Len(t, onlinePeers, 2)
+			assert.NotContains(t, onlinePeers, hosts[2].ID())
+			assert.NotContains(t, onlinePeers, hosts[3].ID())
+		}(el)
+	}
+	wg.Wait()
+}
+
+func TestJoinPartyWithDeadline(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 3)
+	var pcs []*PartyCoordinator
+	var peers []string
+	for _, el := range hosts {
+		pcs = append(pcs, NewPartyCoordinator(el, time.Second*30))
+		peers = append(peers, el.ID().String())
+	}
+	defer func() {
+		for _, el := range pcs {
+			el.Stop()
+		}
+	}()
+
+	joinPartyReq := messages.JoinPartyRequest{
+		ID:        conversion.RandStringBytesMask(64),
+		Threshold: 1,
+	}
+	start := time.Now()
+	// the third node never joins, the deadline ends the join party well before the timeout
+	deadline := time.Now().Add(time.Second * 2)
+	wg := sync.WaitGroup{}
+	for _, el := range pcs[:2] {
+		wg.Add(1)
+		go func(coordinator *PartyCoordinator) {
+			defer wg.Done()
+			_, err := coordinator.JoinPartyWithDeadline(&joinPartyReq, peers, deadline)
+			assert.Equal(t, errJoinPartyTimeout, err)
+		}(el)
+	}
+	wg.Wait()
+	assert.True(t, time.Since(start) < time.Second*10)
+}
@@ -2,15 +2,23 @@ package p2p
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+var errPeerEjected = errors.New("peer is ejected")
+
 type PeerStatus struct {
 	peersResponse  map[peer.ID]bool
+	ejected        map[peer.ID]bool
 	peerStatusLock *sync.RWMutex
 	newFound       chan bool
+	committeeSize  int
+	threshold      int32
+	cancel         chan struct{}
+	cancelOnce     *sync.Once
 }
 
 func NewPeerStatus(peerNodes []peer.ID, myPeerID peer.ID) *PeerStatus {
@@ -23,8 +31,12 @@ func NewPeerStatus(peerNodes []peer.ID, myPeerID peer.ID) *PeerStatus {
 	}
 	peerStatus := &PeerStatus{
 		peersResponse:  dat,
+		ejected:        make(map[peer.ID]bool),
 		peerStatusLock: &sync.RWMutex{},
 		newFound:       make(chan bool, len(peerNodes)),
+		committeeSize:  len(peerNodes),
+		cancel:         make(chan struct{}),
+		cancelOnce:     &sync.Once{},
 	}
 	return peerStatus
 }
@@ -57,9 +69,60 @@ func (ps *PeerStatus) updatePeer(peerNode peer.ID) (bool, error) {
 	if !ok {
 		return false, errors.New("key not found")
 	}
+	if ps.ejected[peerNode] {
+		return false, errPeerEjected
+	}
 	if !val {
 		ps.peersResponse[peerNode] = true
 		return true, nil
 	}
 	return false, nil
 }
+
+// ejectPeer mark the peer offline and ignore its join party requests from now on, it returns false if the peer is
+// not in this party
+func (ps *PeerStatus) ejectPeer(peerNode peer.ID) bool {
+	ps.peerStatusLock.Lock()
+	defer ps.peerStatusLock.Unlock()
+	if _, ok := ps.peersResponse[peerNode]; !ok {
+		return false
+	}
+	ps.peersResponse[peerNode] = false
+	ps.ejected[peerNode] = true
+	return true
+}
+
+// checkThreshold make sure the threshold is meaningful for a committee of the given size
+func checkThreshold(threshold int32, committeeSize int) error {
+	if threshold < 1 || int(threshold) >= committeeSize {
+		return fmt.Errorf("threshold %d is out of range for the committee of %d", threshold, committeeSize)
+	}
+	return nil
+}
+
+// validateThreshold check the threshold announced by the peer is valid and agrees with ours
+func (ps *PeerStatus) validateThreshold(threshold int32) error {
+	if err := checkThreshold(threshold, ps.committeeSize); err != nil {
+		return err
+	}
+	if threshold != ps.threshold {
+		return fmt.Errorf("threshold %d does not match the local threshold %d", threshold, ps.threshold)
+	}
+	return nil
+}
+
+// cancelJoin stop waiting for the rest of the peers, it is safe to call it more than once
+func (ps *PeerStatus) cancelJoin() {
+	ps.cancelOnce.Do(func() {
+		close(ps.cancel)
+	})
+}
+
+func (ps *PeerStatus) isCancelled() bool {
+	select {
+	case <-ps.cancel:
+		return true
+	default:
+		return false
+	}
+}
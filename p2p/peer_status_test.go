@@ -70,3 +70,34 @@ func (s *PeerStatusTestSuite) TestPeerStatus(c *C) {
 	ret = peerStatus.getCoordinationStatus()
 	c.Assert(ret, Equals, true)
 }
+
+func (s *PeerStatusTestSuite) TestValidateThreshold(c *C) {
+	peers := generateRandomPeers(c, 4)
+	peerStatus := NewPeerStatus(peers, peers[0])
+	peerStatus.threshold = 2
+	c.Assert(peerStatus.validateThreshold(2), IsNil)
+	c.Assert(peerStatus.validateThreshold(0), NotNil)
+	c.Assert(peerStatus.validateThreshold(4), NotNil)
+	c.Assert(peerStatus.validateThreshold(1), NotNil)
+	c.Assert(checkThreshold(3, 4), IsNil)
+	c.Assert(checkThreshold(-1, 4), NotNil)
+}
+
+func (s *PeerStatusTestSuite) TestEjectPeer(c *C) {
+	peers := generateRandomPeers(c, 3)
+	peerStatus := NewPeerStatus(peers, peers[0])
+	ret, err := peerStatus.updatePeer(peers[1])
+	c.Assert(err, IsNil)
+	c.Assert(ret, Equals, true)
+
+	c.Assert(peerStatus.ejectPeer(peers[1]), Equals, true)
+	c.Assert(peerStatus.ejectPeer(generateRandomPeers(c, 1)[0]), Equals, false)
+	_, err = peerStatus.updatePeer(peers[1])
+	c.Assert(err, Equals, errPeerEjected)
+	_, err = peerStatus.updatePeer(peers[2])
+	c.Assert(err, IsNil)
+	online, offline := peerStatus.getPeersStatus()
+	c.Assert(online, DeepEquals, []peer.ID{peers[2]})
+	c.Assert(offline, DeepEquals, []peer.ID{peers[1]})
+	c.Assert(peerStatus.getCoordinationStatus(), Equals, false)
+}
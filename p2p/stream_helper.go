@@ -18,6 +18,8 @@ const (
 	TimeoutReadPayload  = time.Second * 10
 	TimeoutWritePayload = time.Second * 10
 	MaxPayload          = 512000 // 512kb
+	// DefaultJoinPartyMaxPayload is the max payload of the join party protocol, its messages are tiny
+	DefaultJoinPartyMaxPayload = 16384 // 16kb
 )
 
 // applyDeadline will be true , and only disable it when we are doing test
@@ -73,8 +75,8 @@ func (sm *StreamMgr) AddStream(msgID string, stream network.Stream) {
 	}
 }
 
-// ReadStreamWithBuffer read data from the given stream
-func ReadStreamWithBuffer(stream network.Stream) ([]byte, error) {
+// ReadStreamWithBuffer read data from the given stream, the payload is limited to maxPayload bytes
+func ReadStreamWithBuffer(stream network.Stream, maxPayload uint32) ([]byte, error) {
 	if ApplyDeadline {
 		if err := stream.SetReadDeadline(time.Now().Add(TimeoutReadPayload)); nil != err {
 			if errReset := stream.Reset(); errReset != nil {
@@ -90,8 +92,8 @@ func ReadStreamWithBuffer(stream network.Stream) ([]byte, error) {
 		return nil, fmt.Errorf("error in read the message head %w", err)
 	}
 	length := binary.LittleEndian.Uint32(lengthBytes)
-	if length > MaxPayload {
-		return nil, fmt.Errorf("payload length:%d exceed max payload length:%d", length, MaxPayload)
+	if length > maxPayload {
+		return nil, fmt.Errorf("payload length:%d exceed max payload length:%d", length, maxPayload)
 	}
 	dataBuf := make([]byte, length)
 	n, err = io.ReadFull(streamReader, dataBuf)
@@ -153,7 +153,7 @@ func TestReadLength(t *testing.T) {
 		ApplyDeadline = true
 		t.Run(tc.name, func(st *testing.T) {
 			stream := tc.streamProvider()
-			l, err := ReadStreamWithBuffer(stream)
+			l, err := ReadStreamWithBuffer(stream, MaxPayload)
 			if tc.expectError && err == nil {
 				st.Errorf("expecting error , however got none")
 				st.FailNow()
@@ -197,7 +197,7 @@ func TestReadPayload(t *testing.T) {
 		ApplyDeadline = true
 		t.Run(tc.name, func(st *testing.T) {
 			stream := tc.streamProvider()
-			l, err := ReadStreamWithBuffer(stream)
+			l, err := ReadStreamWithBuffer(stream, MaxPayload)
 			if err != nil {
 				st.Errorf("fail to read length:%s", err)
 				st.FailNow()
@@ -241,3 +241,45 @@ func TestStreamManager(t *testing.T) {
 	streamMgr.ReleaseStream("3")
 	assert.Equal(t, len(streamMgr.unusedStreams), 0)
 }
+
+func TestReadStreamWithMaxPayload(t *testing.T) {
+	ApplyDeadline = false
+	defer func() {
+		ApplyDeadline = true
+	}()
+	payload := make([]byte, DefaultJoinPartyMaxPayload+1)
+	newStream := func() *MockNetworkStream {
+		stream := NewMockNetworkStream()
+		if err := WriteStreamWithBuffer(payload, stream); err != nil {
+			t.Fatalf("fail to write the data to stream: %s", err)
+		}
+		return stream
+	}
+	// the payload is too large for the join party limit, but fine for the default one
+	if _, err := ReadStreamWithBuffer(newStream(), DefaultJoinPartyMaxPayload); err == nil {
+		t.Fatal("expecting error , however got none")
+	}
+	buf, err := ReadStreamWithBuffer(newStream(), MaxPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(buf), len(payload))
+}
+
+func TestMaxPayloadPerInstance(t *testing.T) {
+	c1 := &Communication{maxPayload: MaxPayload}
+	c2 := &Communication{maxPayload: MaxPayload}
+	c1.SetMaxPayload(DefaultJoinPartyMaxPayload)
+	// the limit of one instance never leaks into the others
+	assert.Equal(t, c1.maxPayload, uint32(DefaultJoinPartyMaxPayload))
+	assert.Equal(t, c2.maxPayload, uint32(MaxPayload))
+	// zero restores the default
+	c1.SetMaxPayload(0)
+	assert.Equal(t, c1.maxPayload, uint32(MaxPayload))
+
+	pc := &PartyCoordinator{maxPayload: DefaultJoinPartyMaxPayload}
+	pc.SetMaxPayload(MaxPayload)
+	assert.Equal(t, pc.maxPayload, uint32(MaxPayload))
+	pc.SetMaxPayload(0)
+	assert.Equal(t, pc.maxPayload, uint32(DefaultJoinPartyMaxPayload))
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package storage
+
+import (
+	"syscall"
+)
+
+// getFreeSpace returns how many bytes are available to a non-privileged user in the given folder
+func getFreeSpace(folder string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(folder, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package storage
+
+import (
+	"math"
+)
+
+// getFreeSpace is not supported on windows, we assume there is always enough space
+func getFreeSpace(_ string) (uint64, error) {
+	return math.MaxUint64, nil
+}
@@ -8,14 +8,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 	ma "github.com/multiformats/go-multiaddr"
 
+	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 )
 
@@ -25,6 +28,14 @@ type KeygenLocalState struct {
 	LocalData       keygen.LocalPartySaveData `json:"local_data"`
 	ParticipantKeys []string                  `json:"participant_keys"` // the paticipant of last key gen
 	LocalPartyKey   string                    `json:"local_party_key"`
+	CreatedAt       time.Time                 `json:"created_at"` // zero for the states saved before we record it
+}
+
+// keyMetadata is the non-secret part of KeygenLocalState, we decode it without touching the share
+type keyMetadata struct {
+	PubKey          string    `json:"pub_key"`
+	ParticipantKeys []string  `json:"participant_keys"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // LocalStateManager provide necessary methods to manage the local state, save it , and read it back
@@ -32,10 +43,34 @@ type KeygenLocalState struct {
 type LocalStateManager interface {
 	SaveLocalState(state KeygenLocalState) error
 	GetLocalState(pubKey string) (KeygenLocalState, error)
+	ListLocalStates() ([]KeygenLocalState, error)
 	SaveAddressBook(addressBook map[peer.ID]addr.AddrList) error
 	RetrieveP2PAddresses() (addr.AddrList, error)
+	CheckFreeSpace(required uint64) error
+	GetKeyMetadata(pubKey string) (committee []string, threshold int, createdAt time.Time, err error)
+}
+
+// ErrInsufficientSpace indicates the state folder does not have enough room to save the keygen result
+var ErrInsufficientSpace = errors.New("insufficient space to save the local state")
+
+const (
+	// localStateBaseSize is the estimated size of the local party secrets(paillier key, safe primes etc)
+	localStateBaseSize = 16 * 1024
+	// localStatePerPartySize is the estimated size of the public values we keep for each party
+	localStatePerPartySize = 4 * 1024
+)
+
+// EstimateLocalStateSize estimate how many bytes the keygen result of the given party size will take once
+// it is saved, we double it to leave some room for the json encoding
+func EstimateLocalStateSize(partyNum int) uint64 {
+	if partyNum < 0 {
+		partyNum = 0
+	}
+	return 2 * (localStateBaseSize + uint64(partyNum)*localStatePerPartySize)
 }
 
+const localStateFilePattern = "localstate-%s.json"
+
 // FileStateMgr save the local state to file
 type FileStateMgr struct {
 	folder    string
@@ -67,7 +102,7 @@ func (fsm *FileStateMgr) getFilePathName(pubKey string) (string, error) {
 		return "", errors.New("invalid pubkey for file name")
 	}
 
-	localFileName := fmt.Sprintf("localstate-%s.json", pubKey)
+	localFileName := fmt.Sprintf(localStateFilePattern, pubKey)
 	if len(fsm.folder) > 0 {
 		return filepath.Join(fsm.folder, localFileName), nil
 	}
@@ -111,6 +146,81 @@ func (fsm *FileStateMgr) GetLocalState(pubKey string) (KeygenLocalState, error)
 	return localState, nil
 }
 
+// GetKeyMetadata return the committee, the threshold and the creation time of the given pool pub key, only the
+// non-secret fields are decoded. The modification time of the file is used for the states that have no creation time
+func (fsm *FileStateMgr) GetKeyMetadata(pubKey string) ([]string, int, time.Time, error) {
+	if len(pubKey) == 0 {
+		return nil, 0, time.Time{}, errors.New("pub key is empty")
+	}
+	filePathName, err := fsm.getFilePathName(pubKey)
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	info, err := os.Stat(filePathName)
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	buf, err := ioutil.ReadFile(filePathName)
+	if err != nil {
+		return nil, 0, time.Time{}, fmt.Errorf("file to read from file(%s): %w", filePathName, err)
+	}
+	var metadata keyMetadata
+	if err := json.Unmarshal(buf, &metadata); nil != err {
+		return nil, 0, time.Time{}, fmt.Errorf("fail to unmarshal the key metadata: %w", err)
+	}
+	createdAt := metadata.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = info.ModTime().UTC()
+	}
+	threshold, err := common.GetThreshold(len(metadata.ParticipantKeys))
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	return metadata.ParticipantKeys, threshold, createdAt, nil
+}
+
+// ListLocalStates read all the local states saved in the folder, they are sorted by pub key
+func (fsm *FileStateMgr) ListLocalStates() ([]KeygenLocalState, error) {
+	folder := fsm.folder
+	if len(folder) == 0 {
+		folder = "."
+	}
+	files, err := filepath.Glob(filepath.Join(folder, fmt.Sprintf(localStateFilePattern, "*")))
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the local state files: %w", err)
+	}
+	var result []KeygenLocalState
+	for _, el := range files {
+		name := filepath.Base(el)
+		pubKey := strings.TrimSuffix(strings.TrimPrefix(name, "localstate-"), ".json")
+		state, err := fsm.GetLocalState(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read the local state of %s: %w", pubKey, err)
+		}
+		result = append(result, state)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].PubKey < result[j].PubKey
+	})
+	return result, nil
+}
+
+// CheckFreeSpace make sure the state folder has at least the given number of bytes available
+func (fsm *FileStateMgr) CheckFreeSpace(required uint64) error {
+	folder := fsm.folder
+	if len(folder) == 0 {
+		folder = "."
+	}
+	free, err := getFreeSpace(folder)
+	if err != nil {
+		return fmt.Errorf("fail to get the free space of folder(%s): %w", folder, err)
+	}
+	if free < required {
+		return fmt.Errorf("%w: required %d bytes, only %d bytes available", ErrInsufficientSpace, required, free)
+	}
+	return nil
+}
+
 func (fsm *FileStateMgr) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	if len(fsm.folder) < 1 {
 		return errors.New("base file path is invalid")
@@ -1,10 +1,13 @@
 package storage
 
 import (
+	"errors"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -74,6 +77,63 @@ func (s *FileStateMgrTestSuite) TestSaveLocalState(c *C) {
 	c.Assert(reflect.DeepEqual(stateItem, item), Equals, true)
 }
 
+func (s *FileStateMgrTestSuite) TestListLocalStates(c *C) {
+	fsm, err := NewFileStateMgr(c.MkDir())
+	c.Assert(err, IsNil)
+	states, err := fsm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 0)
+	pubKeys := []string{
+		"thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+		"thorpub1addwnpepqf90u7n3nr2jwsw4t2gzhzqfdlply8dlzv3mdj4dr22uvhe04azq5gac3gq",
+	}
+	for _, el := range pubKeys {
+		c.Assert(fsm.SaveLocalState(KeygenLocalState{
+			PubKey:          el,
+			LocalData:       keygen.NewLocalPartySaveData(3),
+			ParticipantKeys: []string{"A", "B", "C"},
+			LocalPartyKey:   "A",
+		}), IsNil)
+	}
+	states, err = fsm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 2)
+	c.Assert(states[0].PubKey, Equals, pubKeys[1])
+	c.Assert(states[1].PubKey, Equals, pubKeys[0])
+}
+
+func (s *FileStateMgrTestSuite) TestGetKeyMetadata(c *C) {
+	fsm, err := NewFileStateMgr(c.MkDir())
+	c.Assert(err, IsNil)
+	pubKey := "thorpub1addwnpepqf90u7n3nr2jwsw4t2gzhzqfdlply8dlzv3mdj4dr22uvhe04azq5gac3gq"
+	_, _, _, err = fsm.GetKeyMetadata(pubKey)
+	c.Assert(err, NotNil)
+	_, _, _, err = fsm.GetKeyMetadata("")
+	c.Assert(err, NotNil)
+	createdAt := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	stateItem := KeygenLocalState{
+		PubKey:          pubKey,
+		LocalData:       keygen.NewLocalPartySaveData(4),
+		ParticipantKeys: []string{"A", "B", "C", "D"},
+		LocalPartyKey:   "A",
+		CreatedAt:       createdAt,
+	}
+	c.Assert(fsm.SaveLocalState(stateItem), IsNil)
+	committee, threshold, ts, err := fsm.GetKeyMetadata(pubKey)
+	c.Assert(err, IsNil)
+	c.Assert(committee, DeepEquals, stateItem.ParticipantKeys)
+	c.Assert(threshold, Equals, 2)
+	c.Assert(ts.Equal(createdAt), Equals, true)
+
+	// the states saved before we record the creation time fall back to the modification time of the file
+	stateItem.CreatedAt = time.Time{}
+	c.Assert(fsm.SaveLocalState(stateItem), IsNil)
+	_, _, ts, err = fsm.GetKeyMetadata(pubKey)
+	c.Assert(err, IsNil)
+	c.Assert(ts.IsZero(), Equals, false)
+	c.Assert(time.Since(ts) < time.Minute, Equals, true)
+}
+
 func (s *FileStateMgrTestSuite) TestSaveAddressBook(c *C) {
 	testAddresses := make(map[peer.ID]addr.AddrList)
 	var t *testing.T
@@ -103,3 +163,19 @@ func (s *FileStateMgrTestSuite) TestSaveAddressBook(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(item, HasLen, 3)
 }
+
+func (s *FileStateMgrTestSuite) TestCheckFreeSpace(c *C) {
+	c.Assert(EstimateLocalStateSize(4) > EstimateLocalStateSize(3), Equals, true)
+	c.Assert(EstimateLocalStateSize(-1), Equals, EstimateLocalStateSize(0))
+	folder := os.TempDir()
+	f := filepath.Join(folder, "test")
+	defer func() {
+		err := os.RemoveAll(f)
+		c.Assert(err, IsNil)
+	}()
+	fsm, err := NewFileStateMgr(f)
+	c.Assert(err, IsNil)
+	c.Assert(fsm.CheckFreeSpace(EstimateLocalStateSize(4)), IsNil)
+	err = fsm.CheckFreeSpace(math.MaxUint64)
+	c.Assert(errors.Is(err, ErrInsufficientSpace), Equals, true)
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 )
@@ -17,6 +19,10 @@ func (s *MockLocalStateManager) GetLocalState(pubKey string) (KeygenLocalState,
 	return KeygenLocalState{}, nil
 }
 
+func (s *MockLocalStateManager) ListLocalStates() ([]KeygenLocalState, error) {
+	return nil, nil
+}
+
 func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	return nil
 }
@@ -24,3 +30,11 @@ func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrLis
 func (s *MockLocalStateManager) RetrieveP2PAddresses() (addr.AddrList, error) {
 	return nil, nil
 }
+
+func (s *MockLocalStateManager) CheckFreeSpace(required uint64) error {
+	return nil
+}
+
+func (s *MockLocalStateManager) GetKeyMetadata(pubKey string) ([]string, int, time.Time, error) {
+	return nil, 0, time.Time{}, nil
+}
@@ -0,0 +1,158 @@
+// Package testutil provides the helpers for the tests that run the keygen/keysign ceremonies
+package testutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-peerstore/addr"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// MemStateMgr keeps the local states in memory, so the tests do not touch the disk and do not see the states
+// left by the other tests. The states are kept json encoded, the same way FileStateMgr saves them
+type MemStateMgr struct {
+	lock        *sync.RWMutex
+	states      map[string][]byte
+	addressBook addr.AddrList
+	freeSpace   uint64
+	limitSpace  bool
+}
+
+var _ storage.LocalStateManager = &MemStateMgr{}
+
+// NewMemStateMgr create a new instance of MemStateMgr which implements storage.LocalStateManager
+func NewMemStateMgr() *MemStateMgr {
+	return &MemStateMgr{
+		lock:   &sync.RWMutex{},
+		states: make(map[string][]byte),
+	}
+}
+
+// SetFreeSpace limit the space CheckFreeSpace reports as available, so the tests can simulate a full disk
+func (m *MemStateMgr) SetFreeSpace(free uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.freeSpace = free
+	m.limitSpace = true
+}
+
+// SaveLocalState keep the local state in memory
+func (m *MemStateMgr) SaveLocalState(state storage.KeygenLocalState) error {
+	if len(state.PubKey) == 0 {
+		return errors.New("pub key is empty")
+	}
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("fail to marshal KeygenLocalState to json: %w", err)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.states[state.PubKey] = buf
+	return nil
+}
+
+// GetLocalState return the local state of the given pool pub key, it returns os.ErrNotExist like FileStateMgr if we
+// do not have it
+func (m *MemStateMgr) GetLocalState(pubKey string) (storage.KeygenLocalState, error) {
+	if len(pubKey) == 0 {
+		return storage.KeygenLocalState{}, errors.New("pub key is empty")
+	}
+	m.lock.RLock()
+	buf, ok := m.states[pubKey]
+	m.lock.RUnlock()
+	if !ok {
+		return storage.KeygenLocalState{}, fmt.Errorf("local state of %s: %w", pubKey, os.ErrNotExist)
+	}
+	var localState storage.KeygenLocalState
+	if err := json.Unmarshal(buf, &localState); nil != err {
+		return storage.KeygenLocalState{}, fmt.Errorf("fail to unmarshal KeygenLocalState: %w", err)
+	}
+	return localState, nil
+}
+
+// ListLocalStates return all the local states, they are sorted by pub key
+func (m *MemStateMgr) ListLocalStates() ([]storage.KeygenLocalState, error) {
+	m.lock.RLock()
+	pubKeys := make([]string, 0, len(m.states))
+	for el := range m.states {
+		pubKeys = append(pubKeys, el)
+	}
+	m.lock.RUnlock()
+	sort.Strings(pubKeys)
+	var result []storage.KeygenLocalState
+	for _, el := range pubKeys {
+		state, err := m.GetLocalState(el)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read the local state of %s: %w", el, err)
+		}
+		result = append(result, state)
+	}
+	return result, nil
+}
+
+// GetKeyMetadata return the committee, the threshold and the creation time of the given pool pub key
+func (m *MemStateMgr) GetKeyMetadata(pubKey string) ([]string, int, time.Time, error) {
+	state, err := m.GetLocalState(pubKey)
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	threshold, err := common.GetThreshold(len(state.ParticipantKeys))
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	return state.ParticipantKeys, threshold, state.CreatedAt, nil
+}
+
+// SaveAddressBook keep the addresses of the peers in memory, the loopback addresses are skipped like FileStateMgr
+func (m *MemStateMgr) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
+	var result addr.AddrList
+	for p, addrs := range address {
+		for _, el := range addrs {
+			if strings.Contains(el.String(), "127.0.0.1") {
+				continue
+			}
+			peerAddr, err := ma.NewMultiaddr(el.String() + "/p2p/" + p.String())
+			if err != nil {
+				return fmt.Errorf("invalid address in address book %w", err)
+			}
+			result = append(result, peerAddr)
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.addressBook = result
+	return nil
+}
+
+// RetrieveP2PAddresses return the addresses saved by SaveAddressBook
+func (m *MemStateMgr) RetrieveP2PAddresses() (addr.AddrList, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.addressBook == nil {
+		return nil, fmt.Errorf("address book: %w", os.ErrNotExist)
+	}
+	result := make(addr.AddrList, len(m.addressBook))
+	copy(result, m.addressBook)
+	return result, nil
+}
+
+// CheckFreeSpace always succeeds unless the free space is limited by SetFreeSpace
+func (m *MemStateMgr) CheckFreeSpace(required uint64) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.limitSpace && m.freeSpace < required {
+		return fmt.Errorf("%w: required %d bytes, only %d bytes available", storage.ErrInsufficientSpace, required, m.freeSpace)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package testutil
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-peerstore/addr"
+	maddr "github.com/multiformats/go-multiaddr"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type MemStateMgrTestSuite struct{}
+
+var _ = Suite(&MemStateMgrTestSuite{})
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+func (s *MemStateMgrTestSuite) TestLocalState(c *C) {
+	msm := NewMemStateMgr()
+	stateItem := storage.KeygenLocalState{
+		PubKey:          "pubkey2",
+		LocalData:       keygen.NewLocalPartySaveData(5),
+		ParticipantKeys: []string{"A", "B", "C"},
+		LocalPartyKey:   "A",
+	}
+	_, err := msm.GetLocalState("pubkey2")
+	c.Assert(errors.Is(err, os.ErrNotExist), Equals, true)
+	c.Assert(msm.SaveLocalState(storage.KeygenLocalState{}), NotNil)
+	c.Assert(msm.SaveLocalState(stateItem), IsNil)
+	item, err := msm.GetLocalState(stateItem.PubKey)
+	c.Assert(err, IsNil)
+	c.Assert(reflect.DeepEqual(stateItem, item), Equals, true)
+	// the saved state is not affected by the changes the caller makes afterwards
+	stateItem.ParticipantKeys[0] = "D"
+	item, err = msm.GetLocalState("pubkey2")
+	c.Assert(err, IsNil)
+	c.Assert(item.ParticipantKeys[0], Equals, "A")
+
+	stateItem.PubKey = "pubkey1"
+	c.Assert(msm.SaveLocalState(stateItem), IsNil)
+	states, err := msm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 2)
+	c.Assert(states[0].PubKey, Equals, "pubkey1")
+	c.Assert(states[1].PubKey, Equals, "pubkey2")
+	// every instance has its own states
+	states, err = NewMemStateMgr().ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 0)
+}
+
+func (s *MemStateMgrTestSuite) TestGetKeyMetadata(c *C) {
+	msm := NewMemStateMgr()
+	_, _, _, err := msm.GetKeyMetadata("pubkey")
+	c.Assert(err, NotNil)
+	createdAt := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(msm.SaveLocalState(storage.KeygenLocalState{
+		PubKey:          "pubkey",
+		LocalData:       keygen.NewLocalPartySaveData(3),
+		ParticipantKeys: []string{"A", "B", "C"},
+		LocalPartyKey:   "A",
+		CreatedAt:       createdAt,
+	}), IsNil)
+	committee, threshold, ts, err := msm.GetKeyMetadata("pubkey")
+	c.Assert(err, IsNil)
+	c.Assert(committee, DeepEquals, []string{"A", "B", "C"})
+	c.Assert(threshold, Equals, 1)
+	c.Assert(ts.Equal(createdAt), Equals, true)
+}
+
+func (s *MemStateMgrTestSuite) TestAddressBook(c *C) {
+	msm := NewMemStateMgr()
+	_, err := msm.RetrieveP2PAddresses()
+	c.Assert(err, NotNil)
+	id, err := peer.Decode("16Uiu2HAm4TmEzUqy3q3Dv7HvdoSboHk5sFj2FH3npiN5vDbJC6gh")
+	c.Assert(err, IsNil)
+	local, err := maddr.NewMultiaddr("/ip4/127.0.0.1/tcp/6668")
+	c.Assert(err, IsNil)
+	remote, err := maddr.NewMultiaddr("/ip4/192.168.1.1/tcp/6668")
+	c.Assert(err, IsNil)
+	c.Assert(msm.SaveAddressBook(map[peer.ID]addr.AddrList{
+		id: {local, remote},
+	}), IsNil)
+	addrs, err := msm.RetrieveP2PAddresses()
+	c.Assert(err, IsNil)
+	c.Assert(addrs, HasLen, 1)
+	c.Assert(addrs[0].String(), Equals, "/ip4/192.168.1.1/tcp/6668/p2p/16Uiu2HAm4TmEzUqy3q3Dv7HvdoSboHk5sFj2FH3npiN5vDbJC6gh")
+}
+
+func (s *MemStateMgrTestSuite) TestCheckFreeSpace(c *C) {
+	msm := NewMemStateMgr()
+	c.Assert(msm.CheckFreeSpace(1<<40), IsNil)
+	msm.SetFreeSpace(1024)
+	c.Assert(msm.CheckFreeSpace(1024), IsNil)
+	err := msm.CheckFreeSpace(1025)
+	c.Assert(errors.Is(err, storage.ErrInsufficientSpace), Equals, true)
+}
@@ -13,12 +13,18 @@ import (
 
 func main() {
 
-	n := *(flag.Int("n", 3, "signing party size"))
-	threshold := n - 1
+	partySize := flag.Int("n", 3, "signing party size")
 	export := flag.String("export", "", "path to export keyfile")
 	password := flag.String("password", "", "encryption password for keyfile")
+	verify := flag.Bool("verify", false, "only verify the given shares can reconstruct the pool key and sign with it")
 	flag.Parse()
 	files := flag.Args()
+	n := *partySize
+	threshold := n - 1
+	if len(files) < n {
+		fmt.Printf("---need %d shares, however got %d\n", n, len(files))
+		return
+	}
 
 	setupBech32Prefix()
 	allSecret := make([]KeygenLocalState, len(files))
@@ -43,10 +49,19 @@ func main() {
 
 	tssPrivateKey, err := vssShares[:n].ReConstruct()
 	if err != nil {
-		fmt.Printf("error in tss verify")
+		fmt.Printf("error in tss verify: %v\n", err)
+		return
 	}
 
 	privKey := NewPrivateKey(tssPrivateKey)
+	if *verify {
+		if err := verifyCommittee(privKey, allSecret[:n]); err != nil {
+			fmt.Printf("---committee verification failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("---committee verified, the shares can reconstruct and sign with pool key %s\n", allSecret[0].PubKey)
+		return
+	}
 
 	pk := privKey.PubKey()
 	thorchainpk, address, err := getTssPubKey(pk.X, pk.Y)
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	. "github.com/decred/dcrd/dcrec/secp256k1"
+)
+
+// verifyMessage is the test message we sign with the reconstructed key
+const verifyMessage = "tss-recovery committee verification"
+
+// verifyCommittee check the reconstructed private key belongs to the pool the shares are generated for, and that
+// it produces a signature the pool pub key accepts
+func verifyCommittee(privKey *PrivateKey, shares []KeygenLocalState) error {
+	if len(shares) == 0 {
+		return errors.New("no share is given")
+	}
+	poolPubKey := shares[0].LocalData.ECDSAPub
+	if poolPubKey == nil {
+		return errors.New("the share has no pool pub key")
+	}
+	for _, el := range shares {
+		if el.PubKey != shares[0].PubKey || el.LocalData.ECDSAPub == nil || !el.LocalData.ECDSAPub.Equals(poolPubKey) {
+			return fmt.Errorf("the share of %s does not belong to the pool %s", el.LocalPartyKey, shares[0].PubKey)
+		}
+	}
+	pk := privKey.PubKey()
+	if pk.X.Cmp(poolPubKey.X()) != 0 || pk.Y.Cmp(poolPubKey.Y()) != 0 {
+		return errors.New("the reconstructed key does not match the pool pub key")
+	}
+	hash := sha256.Sum256([]byte(verifyMessage))
+	sig, err := privKey.Sign(hash[:])
+	if err != nil {
+		return fmt.Errorf("fail to sign the test message: %w", err)
+	}
+	if !sig.Verify(hash[:], NewPublicKey(poolPubKey.X(), poolPubKey.Y())) {
+		return errors.New("the pool pub key rejects the signature of the reconstructed key")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tss
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+const (
+	ceremonyKeygen  = "keygen"
+	ceremonyKeysign = "keysign"
+)
+
+type activeCeremony struct {
+	msgID     string
+	kind      string
+	startedAt time.Time
+	tssCommon *common.TssCommon
+}
+
+// ceremonyRegistry keeps track of the keygen/keysign ceremonies this node is currently running
+type ceremonyRegistry struct {
+	lock  *sync.Mutex
+	items map[string]*activeCeremony
+}
+
+func newCeremonyRegistry() *ceremonyRegistry {
+	return &ceremonyRegistry{
+		lock:  &sync.Mutex{},
+		items: make(map[string]*activeCeremony),
+	}
+}
+
+func (cr *ceremonyRegistry) add(msgID, kind string, tssCommon *common.TssCommon) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	cr.items[msgID] = &activeCeremony{
+		msgID:     msgID,
+		kind:      kind,
+		startedAt: time.Now(),
+		tssCommon: tssCommon,
+	}
+}
+
+func (cr *ceremonyRegistry) remove(msgID string) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	delete(cr.items, msgID)
+}
+
+func (cr *ceremonyRegistry) get(msgID string) (*activeCeremony, bool) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	item, ok := cr.items[msgID]
+	return item, ok
+}
+
+// tssCommons return the TssCommon of all the active ceremonies
+func (cr *ceremonyRegistry) tssCommons() []*common.TssCommon {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	result := make([]*common.TssCommon, 0, len(cr.items))
+	for _, item := range cr.items {
+		result = append(result, item.tssCommon)
+	}
+	return result
+}
+
+// list return the active ceremonies, the oldest one comes first
+func (cr *ceremonyRegistry) list() []common.ActiveCeremony {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	result := make([]common.ActiveCeremony, 0, len(cr.items))
+	for _, item := range cr.items {
+		result = append(result, common.ActiveCeremony{
+			MsgID:      item.msgID,
+			Type:       item.kind,
+			StartedAt:  item.startedAt,
+			PeerRounds: item.tssCommon.GetPeerRounds(),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].StartedAt.Equal(result[j].StartedAt) {
+			return result[i].MsgID < result[j].MsgID
+		}
+		return result[i].StartedAt.Before(result[j].StartedAt)
+	})
+	return result
+}
@@ -1,23 +1,33 @@
 package tss
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
 )
 
 func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 	t.tssKeyGenLocker.Lock()
 	defer t.tssKeyGenLocker.Unlock()
+	start := time.Now()
 	status := common.Success
 	msgID, err := t.requestToMsgId(req)
 	if err != nil {
 		return keygen.Response{}, err
 	}
+	// the deadline covers the join party as well as the keygen rounds
+	deadline := t.conf.CeremonyDeadline()
 
 	keygenInstance := keygen.NewTssKeyGen(
 		t.p2pCommunication.GetLocalPeerID(),
@@ -31,23 +41,42 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 		t.privateKey,
 		t.p2pCommunication)
 
+	keygenInstance.SetDeadline(deadline)
+	keygenInstance.GetTssCommonStruct().SetDeadLetterSink(t.deadLetters)
+	t.ceremonies.add(msgID, ceremonyKeygen, keygenInstance.GetTssCommonStruct())
 	keygenMsgChannel := keygenInstance.GetTssKeyGenChannels()
 	t.p2pCommunication.SetSubscribe(messages.TSSKeyGenMsg, msgID, keygenMsgChannel)
 	t.p2pCommunication.SetSubscribe(messages.TSSKeyGenVerMsg, msgID, keygenMsgChannel)
 	t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, msgID, keygenMsgChannel)
 	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, keygenMsgChannel)
+	t.p2pCommunication.SetSubscribe(messages.TSSUnicastAck, msgID, keygenMsgChannel)
 
 	defer func() {
+		t.ceremonies.remove(msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSKeyGenMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSKeyGenVerMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
+		t.p2pCommunication.CancelSubscribe(messages.TSSUnicastAck, msgID)
 
 		t.p2pCommunication.ReleaseStream(msgID)
 		t.partyCoordinator.ReleaseStream(msgID)
 	}()
 
-	onlinePeers, err := t.joinParty(msgID, req.Keys)
+	threshold, err := common.GetThreshold(len(req.Keys))
+	if err != nil {
+		return keygen.Response{}, err
+	}
+	onlinePeers, err := t.joinParty(msgID, req.Keys, threshold, deadline)
+	var committee []string
+	if err != nil && onlinePeers != nil && req.AllowPartialCommittee() &&
+		!errors.Is(err, p2p.ErrJoinPartyCancelled) && !errors.Is(err, p2p.ErrJoinPartyStopped) {
+		committee, err = t.partialCommittee(req, onlinePeers)
+		if err == nil {
+			t.logger.Warn().Msgf("proceed the keygen with %d out of %d nodes", len(committee), len(req.Keys))
+			req.Keys = committee
+		}
+	}
 	if err != nil {
 		if onlinePeers == nil {
 			t.logger.Error().Err(err).Msg("error before we start join party")
@@ -80,9 +109,12 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 		atomic.AddUint64(&t.Status.FailedKeyGen, 1)
 		t.logger.Error().Err(err).Msg("err in keygen")
 		blameNodes := *blameMgr.GetBlame()
+		t.stats.record(ceremonyKeygen, time.Since(start), blameNodes.FailReason)
 		return keygen.NewResponse("", "", common.Fail, blameNodes), err
 	} else {
 		atomic.AddUint64(&t.Status.SucKeyGen, 1)
+		t.stats.record(ceremonyKeygen, time.Since(start), "")
+		t.taskDoneCache.add(msgID, keygenInstance.GetTssCommonStruct())
 	}
 
 	newPubKey, addr, err := conversion.GetTssPubKey(k)
@@ -92,10 +124,29 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 	}
 
 	blameNodes := *blameMgr.GetBlame()
-	return keygen.NewResponse(
+	resp := keygen.NewResponse(
 		newPubKey,
 		addr.String(),
 		status,
 		blameNodes,
-	), nil
+	)
+	if encryptedShare := keygenInstance.GetEncryptedShare(); encryptedShare != nil {
+		resp.EncryptedShare = base64.StdEncoding.EncodeToString(encryptedShare)
+	}
+	resp.Committee = committee
+	return resp, nil
+}
+
+// partialCommittee work out the smaller committee formed by the nodes that joined the party. Every node works it
+// out from its own view of the party, so the keygen fails if the nodes do not see the same peers online
+func (t *TssServer) partialCommittee(req keygen.Request, onlinePeers []peer.ID) ([]string, error) {
+	onlineKeys := make([]string, 0, len(onlinePeers))
+	for _, el := range onlinePeers {
+		pubKey, err := conversion.GetPubKeyFromPeerID(el.String())
+		if err != nil {
+			return nil, fmt.Errorf("fail to get the pub key of peer(%s): %w", el, err)
+		}
+		onlineKeys = append(onlineKeys, pubKey)
+	}
+	return req.PartialCommittee(onlineKeys)
 }
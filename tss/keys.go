@@ -0,0 +1,49 @@
+package tss
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// newKeyListing build the listing of the given local states, the entries and the participant keys are sorted so the
+// hash only depends on the set of keys
+func newKeyListing(states []storage.KeygenLocalState) (common.KeyListing, error) {
+	keys := make([]common.KeyInfo, 0, len(states))
+	for _, el := range states {
+		participants := make([]string, len(el.ParticipantKeys))
+		copy(participants, el.ParticipantKeys)
+		sort.Strings(participants)
+		keys = append(keys, common.KeyInfo{
+			PubKey:          el.PubKey,
+			ParticipantKeys: participants,
+		})
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].PubKey < keys[j].PubKey
+	})
+	buf, err := json.Marshal(keys)
+	if err != nil {
+		return common.KeyListing{}, fmt.Errorf("fail to marshal the key listing: %w", err)
+	}
+	hash, err := common.MsgToHashString(buf)
+	if err != nil {
+		return common.KeyListing{}, fmt.Errorf("fail to hash the key listing: %w", err)
+	}
+	return common.KeyListing{
+		Keys: keys,
+		Hash: hash,
+	}, nil
+}
+
+// GetKeys return the public information of the key shares this node holds
+func (t *TssServer) GetKeys() (common.KeyListing, error) {
+	states, err := t.stateManager.ListLocalStates()
+	if err != nil {
+		return common.KeyListing{}, fmt.Errorf("fail to list the local states: %w", err)
+	}
+	return newKeyListing(states)
+}
@@ -0,0 +1,39 @@
+package tss
+
+import (
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type KeysTestSuite struct{}
+
+var _ = Suite(&KeysTestSuite{})
+
+func (KeysTestSuite) TestNewKeyListing(c *C) {
+	empty, err := newKeyListing(nil)
+	c.Assert(err, IsNil)
+	c.Assert(empty.Keys, HasLen, 0)
+	c.Assert(empty.Hash, Not(Equals), "")
+
+	states := []storage.KeygenLocalState{
+		{PubKey: "pubkey2", ParticipantKeys: []string{"C", "A", "B"}},
+		{PubKey: "pubkey1", ParticipantKeys: []string{"B", "A"}},
+	}
+	listing, err := newKeyListing(states)
+	c.Assert(err, IsNil)
+	c.Assert(listing.Keys, HasLen, 2)
+	c.Assert(listing.Keys[0].PubKey, Equals, "pubkey1")
+	c.Assert(listing.Keys[1].ParticipantKeys, DeepEquals, []string{"A", "B", "C"})
+	// we do not touch the participant keys of the local state
+	c.Assert(states[0].ParticipantKeys, DeepEquals, []string{"C", "A", "B"})
+
+	// the same set of keys in a different order has the same hash
+	reordered, err := newKeyListing([]storage.KeygenLocalState{
+		{PubKey: "pubkey1", ParticipantKeys: []string{"A", "B"}},
+		{PubKey: "pubkey2", ParticipantKeys: []string{"B", "C", "A"}},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(reordered.Hash, Equals, listing.Hash)
+	c.Assert(empty.Hash, Not(Equals), listing.Hash)
+}
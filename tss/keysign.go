@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 
@@ -17,6 +18,7 @@ import (
 )
 
 func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
+	start := time.Now()
 	t.logger.Info().Str("pool pub key", req.PoolPubKey).
 		Str("signer pub keys", strings.Join(req.SignerPubKeys, ",")).
 		Str("msg", req.Message).
@@ -26,6 +28,8 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	if err != nil {
 		return emptyResp, err
 	}
+	// the deadline covers the join party as well as the keysign rounds
+	deadline := t.conf.CeremonyDeadline()
 
 	keysignInstance := keysign.NewTssKeySign(
 		t.p2pCommunication.GetLocalPeerID(),
@@ -38,17 +42,23 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		t.stateManager,
 	)
 
+	keysignInstance.SetDeadline(deadline)
+	keysignInstance.GetTssCommonStruct().SetDeadLetterSink(t.deadLetters)
+	t.ceremonies.add(msgID, ceremonyKeysign, keysignInstance.GetTssCommonStruct())
 	keySignChannels := keysignInstance.GetTssKeySignChannels()
 	t.p2pCommunication.SetSubscribe(messages.TSSKeySignMsg, msgID, keySignChannels)
 	t.p2pCommunication.SetSubscribe(messages.TSSKeySignVerMsg, msgID, keySignChannels)
 	t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, msgID, keySignChannels)
 	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, keySignChannels)
+	t.p2pCommunication.SetSubscribe(messages.TSSUnicastAck, msgID, keySignChannels)
 
 	defer func() {
+		t.ceremonies.remove(msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSKeySignMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSKeySignVerMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, msgID)
 		t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
+		t.p2pCommunication.CancelSubscribe(messages.TSSUnicastAck, msgID)
 
 		t.p2pCommunication.ReleaseStream(msgID)
 		t.signatureNotifier.ReleaseStream(msgID)
@@ -59,6 +69,18 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	if err != nil {
 		return emptyResp, fmt.Errorf("fail to get local keygen state: %w", err)
 	}
+	// the signature is verified against the key we sign with
+	signingPubKey := req.PoolPubKey
+	if req.DerivationIndex != nil {
+		localStateItem.LocalData, err = conversion.DeriveLocalPartySaveData(localStateItem.LocalData, *req.DerivationIndex)
+		if err != nil {
+			return emptyResp, fmt.Errorf("fail to derive the child key: %w", err)
+		}
+		signingPubKey, _, err = conversion.GetTssPubKey(localStateItem.LocalData.ECDSAPub)
+		if err != nil {
+			return emptyResp, fmt.Errorf("fail to get the child pub key: %w", err)
+		}
+	}
 	msgToSign, err := base64.StdEncoding.DecodeString(req.Message)
 	if err != nil {
 		return emptyResp, fmt.Errorf("fail to decode message(%s): %w", req.Message, err)
@@ -66,6 +88,10 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	if len(req.SignerPubKeys) == 0 {
 		return emptyResp, errors.New("empty signer pub keys")
 	}
+	if err := req.CheckSigners(localStateItem.ParticipantKeys); err != nil {
+		t.logger.Error().Err(err).Msg("reject the keysign request")
+		return emptyResp, err
+	}
 
 	threshold, err := common.GetThreshold(len(localStateItem.ParticipantKeys))
 	if err != nil {
@@ -74,24 +100,36 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	}
 	if len(req.SignerPubKeys) <= threshold {
 		t.logger.Error().Msgf("not enough signers, threshold=%d and signers=%d", threshold, len(req.SignerPubKeys))
-		return emptyResp, errors.New("not enough signers")
+		return emptyResp, keysign.ErrInsufficientSigners
 	}
 
 	if !t.isPartOfKeysignParty(req.SignerPubKeys) {
 		// TSS keysign include both form party and keysign itself, thus we wait twice of the timeout
-		data, err := t.signatureNotifier.WaitForSignature(msgID, msgToSign, req.PoolPubKey, t.conf.KeySignTimeout)
+		// we can never hear from more signers than the keysign party has
+		quorum := t.conf.SignatureQuorum
+		if quorum > len(req.SignerPubKeys) {
+			quorum = len(req.SignerPubKeys)
+		}
+		timeout := t.conf.KeySignProtocolTimeout()
+		if !deadline.IsZero() && time.Until(deadline) < timeout {
+			timeout = time.Until(deadline)
+		}
+		data, err := t.signatureNotifier.WaitForSignature(msgID, msgToSign, signingPubKey, timeout, quorum)
 		if err != nil {
 			return emptyResp, fmt.Errorf("fail to get signature:%w", err)
 		}
 		if data == nil || (len(data.S) == 0 && len(data.R) == 0) {
 			return emptyResp, errors.New("keysign failed")
 		}
-		return keysign.NewResponse(
+		resp := keysign.NewResponse(
 			base64.StdEncoding.EncodeToString(data.R),
 			base64.StdEncoding.EncodeToString(data.S),
 			common.Success,
 			blame.Blame{},
-		), nil
+		)
+		t.signatures.add(signingPubKey, req.Message, resp)
+		t.publishResult(signingPubKey, msgToSign, req, resp)
+		return resp, nil
 	}
 	blameMgr := keysignInstance.GetTssCommonStruct().GetBlameMgr()
 	// get all the tss nodes that were part of the original key gen
@@ -100,7 +138,7 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		return emptyResp, fmt.Errorf("fail to convert pub keys to peer id:%w", err)
 	}
 
-	onlinePeers, err := t.joinParty(msgID, req.SignerPubKeys)
+	onlinePeers, err := t.joinParty(msgID, req.SignerPubKeys, threshold, deadline)
 	if err != nil {
 		if onlinePeers == nil {
 			t.logger.Error().Err(err).Msg("error before we start join party")
@@ -133,24 +171,39 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		atomic.AddUint64(&t.Status.FailedKeySign, 1)
 		t.broadcastKeysignFailure(msgID, signers)
 		blameNodes := *blameMgr.GetBlame()
+		t.stats.record(ceremonyKeysign, time.Since(start), blameNodes.FailReason)
 		return keysign.Response{
 			Status: common.Fail,
 			Blame:  blameNodes,
 		}, nil
 	}
 
+	// make sure the committee did not sign with a key other than the one we asked for
+	if err := keysign.VerifySigner(msgToSign, signatureData, signingPubKey); err != nil {
+		t.logger.Error().Err(err).Msg("the signature fails the signer check")
+		atomic.AddUint64(&t.Status.FailedKeySign, 1)
+		t.stats.record(ceremonyKeysign, time.Since(start), "")
+		t.broadcastKeysignFailure(msgID, signers)
+		return emptyResp, fmt.Errorf("fail to verify the signer of the signature: %w", err)
+	}
+
 	atomic.AddUint64(&t.Status.SucKeySign, 1)
+	t.stats.record(ceremonyKeysign, time.Since(start), "")
+	t.taskDoneCache.add(msgID, keysignInstance.GetTssCommonStruct())
 
 	// update signature notification
 	if err := t.signatureNotifier.BroadcastSignature(msgID, signatureData, signers); err != nil {
 		return emptyResp, fmt.Errorf("fail to broadcast signature:%w", err)
 	}
-	return keysign.NewResponse(
+	resp := keysign.NewResponse(
 		base64.StdEncoding.EncodeToString(signatureData.R),
 		base64.StdEncoding.EncodeToString(signatureData.S),
 		common.Success,
 		blame.Blame{},
-	), nil
+	)
+	t.signatures.add(signingPubKey, req.Message, resp)
+	t.publishResult(signingPubKey, msgToSign, req, resp)
+	return resp, nil
 }
 
 func (t *TssServer) broadcastKeysignFailure(messageID string, peers []peer.ID) {
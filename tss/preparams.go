@@ -0,0 +1,64 @@
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/thorchain/tss/go-tss/keygen"
+)
+
+// GeneratedPreParamFile is the file under the base folder we persist the generated pre-parameters to by default
+const GeneratedPreParamFile = "generated_preparams.data"
+
+// ErrPreParamsGenerating indicates another pre-parameter generation is running
+var ErrPreParamsGenerating = errors.New("the pre-parameters are being generated")
+
+// SetPreParamOutput set the file we persist the generated pre-parameters to, it defaults to GeneratedPreParamFile
+// under the base folder
+func (t *TssServer) SetPreParamOutput(filePath string) {
+	t.preParamOutput = filePath
+}
+
+// loadGeneratedPreParams read the pre-parameters we generated before, it returns nil if there are none
+func loadGeneratedPreParams(filePath string) *bkeygen.LocalPreParams {
+	if _, err := os.Stat(filePath); err != nil {
+		return nil
+	}
+	loaded, err := keygen.LoadPreParamsFromFile(filePath)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to load the generated pre-parameters from %s", filePath)
+		return nil
+	}
+	return loaded[0]
+}
+
+// GeneratePreParams generate new pre-parameters for the following keygen, it blocks until the generation is done.
+// The new pre-parameters are persisted to the pre-parameter output file, so the node loads them on restart.
+func (t *TssServer) GeneratePreParams() error {
+	if !atomic.CompareAndSwapInt32(&t.generatingPreParams, 0, 1) {
+		return ErrPreParamsGenerating
+	}
+	defer atomic.StoreInt32(&t.generatingPreParams, 0)
+	t.logger.Info().Msg("start to generate the pre-parameters")
+	preParams, err := bkeygen.GeneratePreParams(t.conf.PreParamTimeout)
+	if err != nil {
+		return fmt.Errorf("fail to generate pre parameters: %w", err)
+	}
+	if !preParams.Validate() {
+		return errors.New("invalid preparams")
+	}
+	if err := keygen.SavePreParams(t.preParamOutput, preParams); err != nil {
+		return err
+	}
+	// the keygen reads the pre-parameters with the lock held
+	t.tssKeyGenLocker.Lock()
+	defer t.tssKeyGenLocker.Unlock()
+	t.preParams = preParams
+	t.logger.Info().Msg("the new pre-parameters are in use")
+	return nil
+}
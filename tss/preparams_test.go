@@ -0,0 +1,29 @@
+package tss
+
+import (
+	"io/ioutil"
+	"path"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/keygen"
+)
+
+type PreParamsTestSuite struct{}
+
+var _ = Suite(&PreParamsTestSuite{})
+
+func (PreParamsTestSuite) TestLoadGeneratedPreParams(c *C) {
+	folder := c.MkDir()
+	filePath := path.Join(folder, GeneratedPreParamFile)
+	c.Assert(loadGeneratedPreParams(filePath), IsNil)
+	c.Assert(ioutil.WriteFile(filePath, []byte("whatever"), 0600), IsNil)
+	c.Assert(loadGeneratedPreParams(filePath), IsNil)
+
+	preParams, err := keygen.LoadPreParamsFromFile(path.Join("../test_data", "preParam_test.data"))
+	c.Assert(err, IsNil)
+	c.Assert(keygen.SavePreParams(filePath, preParams[0]), IsNil)
+	loaded := loadGeneratedPreParams(filePath)
+	c.Assert(loaded, NotNil)
+	c.Assert(loaded.NTildei.Cmp(preParams[0].NTildei), Equals, 0)
+}
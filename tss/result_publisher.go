@@ -0,0 +1,34 @@
+package tss
+
+import (
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+)
+
+// SetResultPublisher set the publisher we emit the successful keysign results to, nil disables the publishing
+func (t *TssServer) SetResultPublisher(publisher keysign.ResultPublisher) {
+	t.resultPublisher = publisher
+}
+
+// publishResult emit the keysign result, the failure to publish does not fail the keysign as the caller still
+// gets the signature in the response
+func (t *TssServer) publishResult(pubKey string, msg []byte, req keysign.Request, resp keysign.Response) {
+	if t.resultPublisher == nil {
+		return
+	}
+	msgHash, err := common.MsgToHashString(msg)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to hash the signed message")
+		return
+	}
+	result := keysign.Result{
+		PoolPubKey:  pubKey,
+		MessageHash: msgHash,
+		R:           resp.R,
+		S:           resp.S,
+		Signers:     req.SignerPubKeys,
+	}
+	if err := t.resultPublisher.Publish(result); err != nil {
+		t.logger.Error().Err(err).Msg("fail to publish the keysign result")
+	}
+}
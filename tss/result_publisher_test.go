@@ -0,0 +1,56 @@
+package tss
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+)
+
+type ResultPublisherTestSuite struct{}
+
+var _ = Suite(&ResultPublisherTestSuite{})
+
+type mockResultPublisher struct {
+	results []keysign.Result
+	fail    bool
+}
+
+func (m *mockResultPublisher) Publish(result keysign.Result) error {
+	if m.fail {
+		return errors.New("you ask for it")
+	}
+	m.results = append(m.results, result)
+	return nil
+}
+
+func (ResultPublisherTestSuite) TestPublishResult(c *C) {
+	t := &TssServer{}
+	req := keysign.NewRequest("pool", "bWVzc2FnZQ==", []string{"signer1", "signer2"})
+	resp := keysign.NewResponse("r", "s", common.Success, blame.Blame{})
+	msg := []byte("message")
+	msgHash, err := common.MsgToHashString(msg)
+	c.Assert(err, IsNil)
+	// no publisher, nothing happens
+	t.publishResult("pool", msg, req, resp)
+
+	publisher := &mockResultPublisher{}
+	t.SetResultPublisher(publisher)
+	t.publishResult("child", msg, req, resp)
+	c.Assert(publisher.results, HasLen, 1)
+	c.Assert(publisher.results[0], DeepEquals, keysign.Result{
+		PoolPubKey:  "child",
+		MessageHash: msgHash,
+		R:           "r",
+		S:           "s",
+		Signers:     []string{"signer1", "signer2"},
+	})
+
+	// the failure to publish is only logged
+	publisher.fail = true
+	t.publishResult("pool", msg, req, resp)
+	c.Assert(publisher.results, HasLen, 1)
+}
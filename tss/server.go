@@ -14,4 +14,9 @@ type Server interface {
 	Keygen(req keygen.Request) (keygen.Response, error)
 	KeySign(req keysign.Request) (keysign.Response, error)
 	GetStatus() common.TssStatus
+	GetStats() common.TssStats
+	GetKeys() (common.KeyListing, error)
+	RebroadcastTaskDone(msgID string) error
+	CancelJoinParty(msgID string) error
+	GeneratePreParams() error
 }
@@ -0,0 +1,58 @@
+package tss
+
+import (
+	"sync"
+
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+)
+
+// signatureCacheSize defines how many signatures we remember, the oldest one is evicted once it is full
+const signatureCacheSize = 4096
+
+// signatureCache keeps the signatures this node has produced or received, indexed by the pool pub key and the
+// message, so that the caller can find out whether a message is signed before it asks for a new keysign
+type signatureCache struct {
+	lock  *sync.Mutex
+	size  int
+	order []string
+	items map[string]keysign.Response
+}
+
+func newSignatureCache(size int) *signatureCache {
+	return &signatureCache{
+		lock:  &sync.Mutex{},
+		size:  size,
+		items: make(map[string]keysign.Response),
+	}
+}
+
+func signatureCacheKey(poolPubKey, message string) string {
+	return poolPubKey + "-" + message
+}
+
+func (sc *signatureCache) add(poolPubKey, message string, resp keysign.Response) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	key := signatureCacheKey(poolPubKey, message)
+	if _, ok := sc.items[key]; !ok {
+		sc.order = append(sc.order, key)
+	}
+	sc.items[key] = resp
+	for len(sc.order) > sc.size {
+		delete(sc.items, sc.order[0])
+		sc.order = sc.order[1:]
+	}
+}
+
+func (sc *signatureCache) get(poolPubKey, message string) (keysign.Response, bool) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	resp, ok := sc.items[signatureCacheKey(poolPubKey, message)]
+	return resp, ok
+}
+
+// HasSigned check whether we have already produced the signature of the given base64 encoded message with the
+// given pool pub key(or the derived child pub key), it returns the cached signature if we have
+func (t *TssServer) HasSigned(poolPubKey, message string) (keysign.Response, bool) {
+	return t.signatures.get(poolPubKey, message)
+}
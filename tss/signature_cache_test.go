@@ -0,0 +1,38 @@
+package tss
+
+import (
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+)
+
+type SignatureCacheTestSuite struct{}
+
+var _ = Suite(&SignatureCacheTestSuite{})
+
+func (SignatureCacheTestSuite) TestSignatureCache(c *C) {
+	sc := newSignatureCache(2)
+	_, ok := sc.get("pool1", "msg1")
+	c.Assert(ok, Equals, false)
+	resp := keysign.NewResponse("r1", "s1", common.Success, blame.Blame{})
+	sc.add("pool1", "msg1", resp)
+	cached, ok := sc.get("pool1", "msg1")
+	c.Assert(ok, Equals, true)
+	c.Assert(cached, DeepEquals, resp)
+	_, ok = sc.get("pool2", "msg1")
+	c.Assert(ok, Equals, false)
+
+	// adding the same signature twice does not take another slot
+	sc.add("pool1", "msg1", resp)
+	sc.add("pool1", "msg2", resp)
+	_, ok = sc.get("pool1", "msg1")
+	c.Assert(ok, Equals, true)
+	// the oldest one is evicted
+	sc.add("pool1", "msg3", resp)
+	_, ok = sc.get("pool1", "msg1")
+	c.Assert(ok, Equals, false)
+	_, ok = sc.get("pool1", "msg3")
+	c.Assert(ok, Equals, true)
+}
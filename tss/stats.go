@@ -0,0 +1,85 @@
+package tss
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+// statsTracker collects the duration and the blame reasons of the finished ceremonies
+type statsTracker struct {
+	lock        *sync.Mutex
+	durations   map[string]time.Duration
+	finished    map[string]uint64
+	blameCounts map[string]uint64
+}
+
+func newStatsTracker() *statsTracker {
+	return &statsTracker{
+		lock:        &sync.Mutex{},
+		durations:   make(map[string]time.Duration),
+		finished:    make(map[string]uint64),
+		blameCounts: make(map[string]uint64),
+	}
+}
+
+func (st *statsTracker) record(kind string, duration time.Duration, failReason string) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.durations[kind] += duration
+	st.finished[kind]++
+	if len(failReason) != 0 {
+		st.blameCounts[failReason]++
+	}
+}
+
+func (st *statsTracker) averageDuration(kind string) time.Duration {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	if st.finished[kind] == 0 {
+		return 0
+	}
+	return st.durations[kind] / time.Duration(st.finished[kind])
+}
+
+func (st *statsTracker) getBlameCounts() map[string]uint64 {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	result := make(map[string]uint64, len(st.blameCounts))
+	for reason, count := range st.blameCounts {
+		result[reason] = count
+	}
+	return result
+}
+
+func newCeremonyStats(successful, failed uint64, avgDuration time.Duration) common.CeremonyStats {
+	stats := common.CeremonyStats{
+		Successful:  successful,
+		Failed:      failed,
+		AvgDuration: avgDuration.Seconds(),
+	}
+	if successful+failed != 0 {
+		stats.SuccessRate = float64(successful) / float64(successful+failed)
+	}
+	return stats
+}
+
+// GetStats return the aggregated statistic of this node
+func (t *TssServer) GetStats() common.TssStats {
+	return common.TssStats{
+		Uptime: time.Since(t.Status.Starttime).Seconds(),
+		Keygen: newCeremonyStats(atomic.LoadUint64(&t.Status.SucKeyGen),
+			atomic.LoadUint64(&t.Status.FailedKeyGen),
+			t.stats.averageDuration(ceremonyKeygen)),
+		Keysign: newCeremonyStats(atomic.LoadUint64(&t.Status.SucKeySign),
+			atomic.LoadUint64(&t.Status.FailedKeySign),
+			t.stats.averageDuration(ceremonyKeysign)),
+		ActiveCeremonies:  t.ceremonies.list(),
+		ConnectedPeers:    len(t.p2pCommunication.GetHost().Network().Peers()),
+		BlameCounts:       t.stats.getBlameCounts(),
+		DroppedMessages:   t.p2pCommunication.GetDroppedMessages(),
+		JoinPartyInFlight: t.partyCoordinator.GetInFlightSends(),
+	}
+}
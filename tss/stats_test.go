@@ -0,0 +1,48 @@
+package tss
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+type StatsTestSuite struct{}
+
+var _ = Suite(&StatsTestSuite{})
+
+func (StatsTestSuite) TestStatsTracker(c *C) {
+	st := newStatsTracker()
+	c.Assert(st.averageDuration(ceremonyKeygen), Equals, time.Duration(0))
+	st.record(ceremonyKeygen, time.Second, "")
+	st.record(ceremonyKeygen, 3*time.Second, blame.TssTimeout)
+	st.record(ceremonyKeysign, time.Second, blame.TssTimeout)
+	c.Assert(st.averageDuration(ceremonyKeygen), Equals, 2*time.Second)
+	c.Assert(st.averageDuration(ceremonyKeysign), Equals, time.Second)
+	counts := st.getBlameCounts()
+	c.Assert(counts, HasLen, 1)
+	c.Assert(counts[blame.TssTimeout], Equals, uint64(2))
+
+	stats := newCeremonyStats(3, 1, time.Second)
+	c.Assert(stats.SuccessRate, Equals, 0.75)
+	c.Assert(stats.AvgDuration, Equals, 1.0)
+	c.Assert(newCeremonyStats(0, 0, 0).SuccessRate, Equals, 0.0)
+}
+
+func (StatsTestSuite) TestCeremonyRegistry(c *C) {
+	cr := newCeremonyRegistry()
+	cr.add("msg1", ceremonyKeygen, common.NewTssCommon("", nil, common.TssConfig{}, "msg1", nil))
+	cr.add("msg2", ceremonyKeysign, common.NewTssCommon("", nil, common.TssConfig{}, "msg2", nil))
+	items := cr.list()
+	c.Assert(items, HasLen, 2)
+	c.Assert(items[0].MsgID, Equals, "msg1")
+	c.Assert(items[1].Type, Equals, ceremonyKeysign)
+	_, ok := cr.get("msg1")
+	c.Assert(ok, Equals, true)
+	cr.remove("msg1")
+	_, ok = cr.get("msg1")
+	c.Assert(ok, Equals, false)
+	c.Assert(cr.list(), HasLen, 1)
+}
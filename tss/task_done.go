@@ -0,0 +1,76 @@
+package tss
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+// taskDoneRetention defines how long do we keep the finished ceremony around for re-broadcasting the task done
+const taskDoneRetention = 2 * time.Minute
+
+var errCeremonyNotFound = errors.New("no recently completed ceremony found for the given message id")
+
+type taskDoneItem struct {
+	tssCommon  *common.TssCommon
+	finishedAt time.Time
+}
+
+// taskDoneCache keeps the recently completed ceremonies, so that we can re-emit the task done notification
+// to the stragglers who missed it
+type taskDoneCache struct {
+	lock      *sync.Mutex
+	retention time.Duration
+	items     map[string]*taskDoneItem
+}
+
+func newTaskDoneCache(retention time.Duration) *taskDoneCache {
+	return &taskDoneCache{
+		lock:      &sync.Mutex{},
+		retention: retention,
+		items:     make(map[string]*taskDoneItem),
+	}
+}
+
+func (tc *taskDoneCache) add(msgID string, tssCommon *common.TssCommon) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.prune()
+	tc.items[msgID] = &taskDoneItem{
+		tssCommon:  tssCommon,
+		finishedAt: time.Now(),
+	}
+}
+
+func (tc *taskDoneCache) get(msgID string) (*common.TssCommon, bool) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.prune()
+	item, ok := tc.items[msgID]
+	if !ok {
+		return nil, false
+	}
+	return item.tssCommon, true
+}
+
+// prune should be called with the lock held
+func (tc *taskDoneCache) prune() {
+	for msgID, item := range tc.items {
+		if time.Since(item.finishedAt) > tc.retention {
+			delete(tc.items, msgID)
+		}
+	}
+}
+
+// RebroadcastTaskDone re-emit the task done notification of a recently completed ceremony, the message id
+// is the same one we derived from the keygen/keysign request
+func (t *TssServer) RebroadcastTaskDone(msgID string) error {
+	tssCommon, ok := t.taskDoneCache.get(msgID)
+	if !ok {
+		return errCeremonyNotFound
+	}
+	t.logger.Info().Str("msgID", msgID).Msg("re-broadcast the task done notification")
+	return tssCommon.NotifyTaskDone()
+}
@@ -0,0 +1,27 @@
+package tss
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+type TaskDoneCacheTestSuite struct{}
+
+var _ = Suite(&TaskDoneCacheTestSuite{})
+
+func (TaskDoneCacheTestSuite) TestTaskDoneCache(c *C) {
+	cache := newTaskDoneCache(time.Second)
+	tssCommon := common.NewTssCommon("", nil, common.TssConfig{}, "msg1", nil)
+	cache.add("msg1", tssCommon)
+	item, ok := cache.get("msg1")
+	c.Assert(ok, Equals, true)
+	c.Assert(item == tssCommon, Equals, true)
+	_, ok = cache.get("msg2")
+	c.Assert(ok, Equals, false)
+	time.Sleep(time.Second + time.Millisecond*100)
+	_, ok = cache.get("msg1")
+	c.Assert(ok, Equals, false)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"sync"
 	"time"
@@ -39,6 +40,15 @@ type TssServer struct {
 	stateManager      storage.LocalStateManager
 	signatureNotifier *keysign.SignatureNotifier
 	privateKey        tcrypto.PrivKey
+	taskDoneCache     *taskDoneCache
+	ceremonies        *ceremonyRegistry
+	stats             *statsTracker
+	deadLetters       *common.DeadLetterSink
+	signatures        *signatureCache
+	resultPublisher   keysign.ResultPublisher
+	preParamOutput    string
+	// generatingPreParams is 1 while the pre-parameters are being generated
+	generatingPreParams int32
 }
 
 // NewTss create a new instance of Tss
@@ -62,6 +72,14 @@ func NewTss(
 		return nil, fmt.Errorf("fail to create file state manager")
 	}
 
+	var deadLetters *common.DeadLetterSink
+	if len(conf.DeadLetterFile) != 0 {
+		deadLetters, err = common.NewDeadLetterSink(conf.DeadLetterFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to create the dead letter sink: %w", err)
+		}
+	}
+
 	var bootstrapPeers addr.AddrList
 	savedPeers, err := stateManager.RetrieveP2PAddresses()
 	if err != nil {
@@ -74,11 +92,23 @@ func NewTss(
 	if err != nil {
 		return nil, fmt.Errorf("fail to create communication layer: %w", err)
 	}
+	if len(conf.AnnounceAllowCIDRs) != 0 || len(conf.AnnounceDenyCIDRs) != 0 {
+		addrFilter, err := p2p.NewAddrFilter(conf.AnnounceAllowCIDRs, conf.AnnounceDenyCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("fail to create the announce address filter: %w", err)
+		}
+		comm.SetAddrFilter(addrFilter)
+	}
+	comm.SetMaxPayload(uint32(conf.TssMaxPayload))
 	// When using the keygen party it is recommended that you pre-compute the
 	// "safe primes" and Paillier secret beforehand because this can take some
 	// time.
 	// This code will generate those parameters using a concurrency limit equal
 	// to the number of available CPU cores.
+	preParamOutput := filepath.Join(baseFolder, GeneratedPreParamFile)
+	if preParams == nil {
+		preParams = loadGeneratedPreParams(preParamOutput)
+	}
 	if preParams == nil || !preParams.Validate() {
 		preParams, err = bkeygen.GeneratePreParams(conf.PreParamTimeout)
 		if err != nil {
@@ -96,8 +126,12 @@ func NewTss(
 	if err := comm.Start(priKeyRawBytes); nil != err {
 		return nil, fmt.Errorf("fail to start p2p network: %w", err)
 	}
-	pc := p2p.NewPartyCoordinator(comm.GetHost(), conf.PartyTimeout)
-	sn := keysign.NewSignatureNotifier(comm.GetHost())
+	// the context of the communication is the parent of all the other components, so that stopping the
+	// communication cancels all the join parties and the network calls in progress
+	pc := p2p.NewPartyCoordinatorWithContext(comm.Context(), comm.GetHost(), conf.JoinPartyTimeout)
+	pc.SetSendConcurrency(conf.JoinPartyConcurrency)
+	pc.SetMaxPayload(uint32(conf.JoinPartyMaxPayload))
+	sn := keysign.NewSignatureNotifierWithContext(comm.Context(), comm.GetHost())
 	tssServer := TssServer{
 		conf:   conf,
 		logger: log.With().Str("module", "tss").Logger(),
@@ -113,11 +147,37 @@ func NewTss(
 		stateManager:      stateManager,
 		signatureNotifier: sn,
 		privateKey:        priKey,
+		taskDoneCache:     newTaskDoneCache(taskDoneRetention),
+		ceremonies:        newCeremonyRegistry(),
+		stats:             newStatsTracker(),
+		deadLetters:       deadLetters,
+		signatures:        newSignatureCache(signatureCacheSize),
+		preParamOutput:    preParamOutput,
 	}
 
 	return &tssServer, nil
 }
 
+// NewTssFromKeystore works like NewTss, except that it loads the private key from the keystore file and decrypts
+// it with the given passphrase
+func NewTssFromKeystore(
+	cmdBootstrapPeers addr.AddrList,
+	p2pPort int,
+	keystoreFile,
+	passphrase,
+	rendezvous,
+	baseFolder string,
+	conf common.TssConfig,
+	preParams *bkeygen.LocalPreParams,
+	externalIP string,
+) (*TssServer, error) {
+	priKey, err := conversion.LoadKeystore(keystoreFile, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load the private key from the keystore: %w", err)
+	}
+	return NewTss(cmdBootstrapPeers, p2pPort, priKey, rendezvous, baseFolder, conf, preParams, externalIP)
+}
+
 // Start Tss server
 func (t *TssServer) Start() error {
 	log.Info().Msg("Starting the TSS servers")
@@ -134,9 +194,14 @@ func (t *TssServer) Stop() {
 		t.logger.Error().Msgf("error in shutdown the p2p server")
 	}
 	t.partyCoordinator.Stop()
+	if err := t.deadLetters.Close(); err != nil {
+		t.logger.Error().Err(err).Msg("fail to close the dead letter file")
+	}
 	log.Info().Msg("The Tss and p2p server has been stopped successfully")
 }
 
+var errJoinPartyNotFound = errors.New("no join party in progress for the given message id")
+
 func (t *TssServer) requestToMsgId(request interface{}) (string, error) {
 	var dat []byte
 	var keys []string
@@ -151,6 +216,9 @@ func (t *TssServer) requestToMsgId(request interface{}) (string, error) {
 		}
 		keys = value.SignerPubKeys
 		dat = msgToSign
+		if value.DerivationIndex != nil {
+			dat = append(dat, []byte(fmt.Sprintf("derive-%d", *value.DerivationIndex))...)
+		}
 	default:
 		t.logger.Error().Msg("unknown request type")
 		return "", errors.New("unknown request type")
@@ -164,19 +232,61 @@ func (t *TssServer) requestToMsgId(request interface{}) (string, error) {
 	return common.MsgToHashString(dat)
 }
 
-func (t *TssServer) joinParty(msgID string, keys []string) ([]peer.ID, error) {
+func (t *TssServer) joinParty(msgID string, keys []string, threshold int, deadline time.Time) ([]peer.ID, error) {
 	peerIDs, err := conversion.GetPeerIDsFromPubKeys(keys)
 	if err != nil {
 		return nil, fmt.Errorf("fail to convert pub key to peer id: %w", err)
 	}
 
 	joinPartyReq := &messages.JoinPartyRequest{
-		ID: msgID,
+		ID:        msgID,
+		Threshold: int32(threshold),
 	}
-	onlinePeers, err := t.partyCoordinator.JoinPartyWithRetry(joinPartyReq, peerIDs)
+	onlinePeers, err := t.partyCoordinator.JoinPartyWithDeadline(joinPartyReq, peerIDs, deadline)
 	return onlinePeers, err
 }
 
+// CancelJoinParty abort the join party of the keygen/keysign with the given message id, the ceremony fails with
+// the peers that have not joined so far blamed
+func (t *TssServer) CancelJoinParty(msgID string) error {
+	if !t.partyCoordinator.CancelJoinParty(msgID) {
+		return errJoinPartyNotFound
+	}
+	t.logger.Info().Str("msgID", msgID).Msg("cancel the join party")
+	return nil
+}
+
+// EjectPeer remove the given peer from all the active ceremonies, the peer is treated as offline in the parties
+// that are still forming, its messages are dropped in the ceremonies that are running, and its connection is closed
+func (t *TssServer) EjectPeer(peerID string) error {
+	pid, err := peer.Decode(peerID)
+	if err != nil {
+		return fmt.Errorf("fail to decode the peer id(%s): %w", peerID, err)
+	}
+	if pid == t.p2pCommunication.GetHost().ID() {
+		return errors.New("cannot eject ourselves")
+	}
+	msgIDs := t.partyCoordinator.EjectPeer(pid)
+	t.logger.Info().Msgf("eject peer %s from the join parties %v", peerID, msgIDs)
+	for _, el := range t.ceremonies.tssCommons() {
+		el.EjectPeer(pid)
+	}
+	if err := t.p2pCommunication.GetHost().Network().ClosePeer(pid); err != nil {
+		return fmt.Errorf("fail to close the connection to peer(%s): %w", peerID, err)
+	}
+	return nil
+}
+
+// Pause hold the p2p message processing for maintenance without dropping the connections
+func (t *TssServer) Pause() {
+	t.p2pCommunication.Pause()
+}
+
+// Resume the p2p message processing
+func (t *TssServer) Resume() {
+	t.p2pCommunication.Resume()
+}
+
 // GetLocalPeerID return the local peer
 func (t *TssServer) GetLocalPeerID() string {
 	return t.p2pCommunication.GetLocalPeerID()
@@ -184,5 +294,7 @@ func (t *TssServer) GetLocalPeerID() string {
 
 // GetStatus return the TssStatus
 func (t *TssServer) GetStatus() common.TssStatus {
-	return t.Status
+	status := t.Status
+	status.DroppedMessages = t.p2pCommunication.GetDroppedMessages()
+	return status
 }